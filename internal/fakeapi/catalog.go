@@ -0,0 +1,216 @@
+package fakeapi
+
+import (
+	"net/http"
+)
+
+// catalog holds read-only order options.
+// Every location offers the same server models and every server model the same options.
+type catalog struct {
+	locations           []Object
+	serverModels        []Object
+	ramOptions          []Object
+	operatingSystems    []Object
+	driveModels         []Object
+	uplinkModels        []Object
+	bandwidth           []Object
+	sbmFlavors          []Object
+	sbmOperatingSystems []Object
+	rbsFlavors          []Object
+	locationGroups      []Object
+	networkPools        []Object
+	cloudRegions        []Object
+	cloudImages         []Object
+	cloudFlavors        []Object
+}
+
+// newCatalog returns the catalog matching the acceptance tests configurations
+func newCatalog() *catalog {
+	return &catalog{
+		locations: []Object{
+			{"id": 1, "name": "San Jose", "status": "active", "code": "SJC1", "supported_features": []string{"disaggregated_public_ports", "disaggregated_private_ports", "no_public_network", "no_private_ip", "host_rescue_mode", "oob_public_access"}, "l2_segments_enabled": true, "private_racks_enabled": true, "load_balancers_enabled": true},
+			{"id": 2, "name": "Amsterdam", "status": "active", "code": "AMS1", "supported_features": []string{"host_rescue_mode"}, "l2_segments_enabled": true, "private_racks_enabled": false, "load_balancers_enabled": false},
+		},
+		serverModels: []Object{
+			{
+				"id": 1, "name": "Dell R440 / 2xIntel Xeon Silver-4114 / 32 GB RAM / 1x480 GB SSD",
+				"cpu_name": "Intel Xeon Silver-4114", "cpu_count": 2, "cpu_cores_count": 20, "cpu_frequency": 2200,
+				"ram": 32, "ram_type": "DDR4", "max_ram": 384, "has_raid_controller": true, "raid_controller_name": "PERC H330",
+				"drive_slots_count": 4,
+				"drive_slots": []Object{
+					{"position": 0, "interface": "SATA3", "form_factor": "2.5", "drive_model_id": 1, "hot_swappable": true},
+					{"position": 1, "interface": "SATA3", "form_factor": "2.5", "drive_model_id": nil, "hot_swappable": true},
+					{"position": 2, "interface": "SATA3", "form_factor": "2.5", "drive_model_id": nil, "hot_swappable": true},
+					{"position": 3, "interface": "SATA3", "form_factor": "2.5", "drive_model_id": nil, "hot_swappable": true},
+				},
+			},
+		},
+		ramOptions: []Object{
+			{"ram": 32, "type": "DDR4"},
+			{"ram": 64, "type": "DDR4"},
+		},
+		operatingSystems: []Object{
+			{"id": 1, "full_name": "Ubuntu 16.04-server x86_64", "name": "Ubuntu", "version": "16.04-server", "arch": "x86_64", "filesystems": []string{"ext2", "ext4", "swap", "xfs", "reiser"}},
+			{"id": 2, "full_name": "Ubuntu 18.04-server x86_64", "name": "Ubuntu", "version": "18.04-server", "arch": "x86_64", "filesystems": []string{"ext2", "ext4", "swap", "xfs", "reiser"}},
+		},
+		driveModels: []Object{
+			{"id": 1, "name": "480 GB SSD SATA", "capacity": 480, "interface": "SATA3", "form_factor": "2.5", "media_type": "SSD"},
+			{"id": 2, "name": "1 TB HDD SATA", "capacity": 1000, "interface": "SATA3", "form_factor": "2.5", "media_type": "HDD"},
+		},
+		uplinkModels: []Object{
+			{"id": 1, "name": "Public 10 Gbps with redundancy", "type": "public", "speed": 10000, "redundancy": true},
+			{"id": 2, "name": "Private 10 Gbps with redundancy", "type": "private", "speed": 10000, "redundancy": true},
+		},
+		bandwidth: []Object{
+			{"id": 1, "name": "19.1 TB", "type": "bytes", "commit": 21000000000000},
+			{"id": 2, "name": "Unmetered", "type": "unmetered"},
+		},
+		sbmFlavors: []Object{
+			{"id": 1, "name": "SBM-01", "cpu_name": "Intel Xeon E-2274G", "cpu_count": 1, "cpu_cores_count": 4, "cpu_frequency": "4.0", "ram_size": 32, "drives_configuration": "2 x 480 GB SSD", "public_uplink_model_id": 1, "public_uplink_model_name": "Public 10 Gbps with redundancy", "private_uplink_model_id": 2, "private_uplink_model_name": "Private 10 Gbps with redundancy", "bandwidth_id": 1, "bandwidth_name": "19.1 TB"},
+		},
+		sbmOperatingSystems: []Object{
+			{"id": 2, "full_name": "Ubuntu 18.04-server x86_64", "name": "Ubuntu", "version": "18.04-server", "arch": "x86_64", "filesystems": []string{"ext4", "swap"}},
+		},
+		rbsFlavors: []Object{
+			{"id": 1, "name": "ssd-1", "iops_per_gb": 10.0, "bandwidth_per_gb": 0.5, "min_size_gb": 1},
+		},
+		locationGroups: []Object{
+			{"id": 1, "name": "San Jose", "code": "SJC1", "group_type": "public", "location_ids": []int64{1}},
+			{"id": 2, "name": "San Jose", "code": "SJC1", "group_type": "private", "location_ids": []int64{1}},
+		},
+		networkPools: []Object{
+			{"id": "pool1", "title": "Private pool", "cidr": "10.0.0.0/16", "type": "private", "location_ids": []int64{1}, "labels": map[string]string{}, "created_at": "2020-01-01T00:00:00Z", "updated_at": "2020-01-01T00:00:00Z"},
+		},
+		cloudRegions: []Object{
+			{"id": 1, "name": "San Jose", "code": "SJC1"},
+		},
+		cloudImages: []Object{
+			{"id": "img1", "name": "Ubuntu 18.04-server x86_64"},
+		},
+		cloudFlavors: []Object{
+			{"id": "flv1", "name": "SSD.30"},
+			{"id": "flv2", "name": "SSD.50"},
+		},
+	}
+}
+
+func findByID(objects []Object, id interface{}) (Object, bool) {
+	for _, object := range objects {
+		if toInt64(object["id"]) == toInt64(id) {
+			return object, true
+		}
+	}
+
+	return nil, false
+}
+
+func findByStringID(objects []Object, id string) (Object, bool) {
+	for _, object := range objects {
+		if object["id"] == id {
+			return object, true
+		}
+	}
+
+	return nil, false
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	}
+
+	return -1
+}
+
+// serverModelOption returns the server model without details
+func serverModelOption(object Object) Object {
+	option := copyObject(object)
+	delete(option, "drive_slots")
+
+	return option
+}
+
+func (s *Server) registerCatalogRoutes(mux *http.ServeMux) {
+	c := s.catalog
+
+	// withLocation checks the location from the path exists
+	withLocation := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := findByID(c.locations, pathInt(r, "location_id")); !ok {
+				writeNotFound(w, "Location")
+				return
+			}
+			next(w, r)
+		}
+	}
+
+	// withServerModel checks the location and server model from the path exist
+	withServerModel := func(next http.HandlerFunc) http.HandlerFunc {
+		return withLocation(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := findByID(c.serverModels, pathInt(r, "server_model_id")); !ok {
+				writeNotFound(w, "Server model")
+				return
+			}
+			next(w, r)
+		})
+	}
+
+	listHandler := func(items []Object) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			writeList(w, r, items)
+		}
+	}
+
+	getHandler := func(items []Object, param, what string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			item, ok := findByID(items, pathInt(r, param))
+			if !ok {
+				writeNotFound(w, what)
+				return
+			}
+			writeJSON(w, http.StatusOK, item)
+		}
+	}
+
+	serverModels := make([]Object, 0, len(c.serverModels))
+	for _, serverModel := range c.serverModels {
+		serverModels = append(serverModels, serverModelOption(serverModel))
+	}
+
+	const orderOptions = "/locations/{location_id}/order_options"
+	const serverModel = orderOptions + "/server_models/{server_model_id}"
+
+	mux.HandleFunc("GET /locations", listHandler(c.locations))
+	mux.HandleFunc("GET /locations/{location_id}", getHandler(c.locations, "location_id", "Location"))
+
+	mux.HandleFunc("GET "+orderOptions+"/server_models", withLocation(listHandler(serverModels)))
+	mux.HandleFunc("GET "+serverModel, withLocation(getHandler(c.serverModels, "server_model_id", "Server model")))
+	mux.HandleFunc("GET "+serverModel+"/ram", withServerModel(listHandler(c.ramOptions)))
+	mux.HandleFunc("GET "+serverModel+"/operating_systems", withServerModel(listHandler(c.operatingSystems)))
+	mux.HandleFunc("GET "+serverModel+"/operating_systems/{operating_system_id}", withServerModel(getHandler(c.operatingSystems, "operating_system_id", "Operating system")))
+	mux.HandleFunc("GET "+serverModel+"/drive_models", withServerModel(listHandler(c.driveModels)))
+	mux.HandleFunc("GET "+serverModel+"/drive_models/{drive_model_id}", withServerModel(getHandler(c.driveModels, "drive_model_id", "Drive model")))
+	mux.HandleFunc("GET "+serverModel+"/uplink_models", withServerModel(listHandler(c.uplinkModels)))
+	mux.HandleFunc("GET "+serverModel+"/uplink_models/{uplink_model_id}", withServerModel(getHandler(c.uplinkModels, "uplink_model_id", "Uplink model")))
+	mux.HandleFunc("GET "+serverModel+"/uplink_models/{uplink_model_id}/bandwidth", withServerModel(listHandler(c.bandwidth)))
+	mux.HandleFunc("GET "+serverModel+"/uplink_models/{uplink_model_id}/bandwidth/{bandwidth_id}", withServerModel(getHandler(c.bandwidth, "bandwidth_id", "Bandwidth")))
+
+	mux.HandleFunc("GET "+orderOptions+"/sbm_flavor_models", withLocation(listHandler(c.sbmFlavors)))
+	mux.HandleFunc("GET "+orderOptions+"/sbm_flavor_models/{sbm_flavor_model_id}", withLocation(getHandler(c.sbmFlavors, "sbm_flavor_model_id", "SBM flavor model")))
+	mux.HandleFunc("GET "+orderOptions+"/sbm_flavor_models/{sbm_flavor_model_id}/operating_systems", withLocation(listHandler(c.sbmOperatingSystems)))
+	mux.HandleFunc("GET "+orderOptions+"/sbm_flavor_models/{sbm_flavor_model_id}/operating_systems/{operating_system_id}", withLocation(getHandler(c.sbmOperatingSystems, "operating_system_id", "Operating system")))
+
+	mux.HandleFunc("GET "+orderOptions+"/remote_block_storage/flavors", withLocation(listHandler(c.rbsFlavors)))
+	mux.HandleFunc("GET "+orderOptions+"/remote_block_storage/flavors/{flavor_id}", withLocation(getHandler(c.rbsFlavors, "flavor_id", "Remote block storage flavor")))
+
+	mux.HandleFunc("GET /l2_segments/location_groups", listHandler(c.locationGroups))
+
+	mux.HandleFunc("GET /cloud_computing/regions", listHandler(c.cloudRegions))
+	mux.HandleFunc("GET /cloud_computing/regions/{region_id}/images", listHandler(c.cloudImages))
+	mux.HandleFunc("GET /cloud_computing/regions/{region_id}/flavors", listHandler(c.cloudFlavors))
+}
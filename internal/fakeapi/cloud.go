@@ -0,0 +1,208 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type cloudInstanceInput struct {
+	Name              *string           `json:"name"`
+	RegionID          int64             `json:"region_id"`
+	FlavorID          string            `json:"flavor_id"`
+	ImageID           string            `json:"image_id"`
+	GPNEnabled        *bool             `json:"gpn_enabled"`
+	IPv4Enabled       *bool             `json:"ipv4_enabled"`
+	IPv6Enabled       *bool             `json:"ipv6_enabled"`
+	SSHKeyFingerprint *string           `json:"ssh_key_fingerprint"`
+	BackupCopies      *int              `json:"backup_copies"`
+	UserData          *string           `json:"user_data"`
+	Labels            map[string]string `json:"labels"`
+}
+
+func (s *Server) registerCloudRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /cloud_computing/instances", s.listCloudInstances)
+	mux.HandleFunc("POST /cloud_computing/instances", s.createCloudInstance)
+	mux.HandleFunc("GET /cloud_computing/instances/{id}", s.getCloudInstance)
+	mux.HandleFunc("PUT /cloud_computing/instances/{id}", s.updateCloudInstance)
+	mux.HandleFunc("DELETE /cloud_computing/instances/{id}", s.deleteCloudInstance)
+	mux.HandleFunc("POST /cloud_computing/instances/{id}/upgrade", s.upgradeCloudInstance)
+}
+
+func (s *Server) listCloudInstances(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+
+	writeList(w, r, s.list(KindCloudInstance, func(object Object) bool {
+		if regionID := query.Get("region_id"); regionID != "" && fmt.Sprint(object["region_id"]) != regionID {
+			return false
+		}
+		return matchListQuery(query, object, "name")
+	}))
+}
+
+func (s *Server) createCloudInstance(w http.ResponseWriter, r *http.Request) {
+	var input cloudInstanceInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.catalog
+
+	region, ok := findByID(c.cloudRegions, input.RegionID)
+	if !ok {
+		writeValidationError(w, "region_id", "is invalid")
+		return
+	}
+	flavor, ok := findByStringID(c.cloudFlavors, input.FlavorID)
+	if !ok {
+		writeValidationError(w, "flavor_id", "is invalid")
+		return
+	}
+	image, ok := findByStringID(c.cloudImages, input.ImageID)
+	if !ok {
+		writeValidationError(w, "image_id", "is invalid")
+		return
+	}
+	if input.Name == nil || *input.Name == "" {
+		writeValidationError(w, "name", "can't be blank")
+		return
+	}
+
+	id := s.newID()
+
+	var publicIPv4, publicIPv6 interface{}
+	if input.IPv4Enabled == nil || *input.IPv4Enabled {
+		publicIPv4 = fmt.Sprintf("203.0.%d.%d", s.nextID/250, s.nextID%250+1)
+	}
+	ipv6Enabled := input.IPv6Enabled != nil && *input.IPv6Enabled
+	if ipv6Enabled {
+		publicIPv6 = fmt.Sprintf("2001:db8::%x", s.nextID)
+	}
+
+	backupCopies := 0
+	if input.BackupCopies != nil {
+		backupCopies = *input.BackupCopies
+	}
+
+	labels := input.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	instance := s.create(KindCloudInstance, Object{
+		"id":                   id,
+		"name":                 *input.Name,
+		"openstack_uuid":       fmt.Sprintf("00000000-0000-0000-0000-%012d", s.nextID),
+		"flavor_id":            flavor["id"],
+		"flavor_name":          flavor["name"],
+		"image_id":             image["id"],
+		"image_name":           image["name"],
+		"public_ipv4_address":  publicIPv4,
+		"private_ipv4_address": nil,
+		"local_ipv4_address":   fmt.Sprintf("10.1.%d.%d", s.nextID/250, s.nextID%250+1),
+		"public_ipv6_address":  publicIPv6,
+		"gpn_enabled":          input.GPNEnabled != nil && *input.GPNEnabled,
+		"ipv6_enabled":         ipv6Enabled,
+		"backup_copies":        backupCopies,
+		"public_port_blocked":  false,
+		"region_id":            region["id"],
+		"region_code":          region["code"],
+		"labels":               labels,
+	})
+
+	writeJSON(w, http.StatusCreated, instance)
+}
+
+func (s *Server) getCloudInstance(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	instance, ok := s.read(KindCloudInstance, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w, "Cloud computing instance")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, instance)
+}
+
+func (s *Server) updateCloudInstance(w http.ResponseWriter, r *http.Request) {
+	var input cloudInstanceInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fields := Object{}
+	if input.Name != nil {
+		fields["name"] = *input.Name
+	}
+	if input.BackupCopies != nil {
+		fields["backup_copies"] = *input.BackupCopies
+	}
+	if input.IPv6Enabled != nil {
+		fields["ipv6_enabled"] = *input.IPv6Enabled
+	}
+	if input.GPNEnabled != nil {
+		fields["gpn_enabled"] = *input.GPNEnabled
+	}
+	if input.Labels != nil {
+		fields["labels"] = input.Labels
+	}
+
+	instance, ok := s.update(KindCloudInstance, r.PathValue("id"), fields)
+	if !ok {
+		writeNotFound(w, "Cloud computing instance")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, instance)
+}
+
+func (s *Server) upgradeCloudInstance(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		FlavorID string `json:"flavor_id"`
+	}
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flavor, ok := findByStringID(s.catalog.cloudFlavors, input.FlavorID)
+	if !ok {
+		writeValidationError(w, "flavor_id", "is invalid")
+		return
+	}
+
+	instance, ok := s.update(KindCloudInstance, r.PathValue("id"), Object{
+		"flavor_id":   flavor["id"],
+		"flavor_name": flavor["name"],
+	})
+	if !ok {
+		writeNotFound(w, "Cloud computing instance")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, instance)
+}
+
+func (s *Server) deleteCloudInstance(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.delete(KindCloudInstance, r.PathValue("id")) {
+		writeNotFound(w, "Cloud computing instance")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, nil)
+}
@@ -0,0 +1,112 @@
+package fakeapi
+
+import (
+	"net/http"
+	"slices"
+)
+
+// hostFeatures are the features of dedicated servers, all of them are deactivated initially
+var hostFeatures = []string{
+	"disaggregated_public_ports",
+	"host_rescue_mode",
+	"no_public_network",
+	"oob_public_access",
+	"private_ipxe_boot",
+}
+
+type hostFeatureInput struct {
+	AuthMethods        []string `json:"auth_methods"`
+	SSHKeyFingerprints []string `json:"ssh_key_fingerprints"`
+	IPXEConfig         string   `json:"ipxe_config"`
+}
+
+func (s *Server) registerHostFeatureRoutes(mux *http.ServeMux) {
+	const features = "/hosts/dedicated_servers/{id}/features"
+
+	mux.HandleFunc("GET "+features, s.listHostFeatures)
+	mux.HandleFunc("POST "+features+"/{name}/activate", s.switchHostFeature("activation"))
+	mux.HandleFunc("POST "+features+"/{name}/deactivate", s.switchHostFeature("deactivation"))
+}
+
+// hostFeatureStatuses returns statuses of the dedicated server features,
+// a transition started by the previous request is finished by the read
+func (s *Server) hostFeatureStatuses(id string) map[string]string {
+	statuses, ok := s.features[id]
+	if !ok {
+		statuses = make(map[string]string, len(hostFeatures))
+		for _, name := range hostFeatures {
+			statuses[name] = "deactivated"
+		}
+		s.features[id] = statuses
+	}
+
+	return statuses
+}
+
+func (s *Server) listHostFeatures(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.objects[KindDedicatedServer][id]; !ok {
+		writeNotFound(w, "Dedicated server")
+		return
+	}
+
+	statuses := s.hostFeatureStatuses(id)
+
+	features := make([]Object, 0, len(hostFeatures))
+	for _, name := range hostFeatures {
+		features = append(features, Object{"name": name, "status": statuses[name]})
+
+		switch statuses[name] {
+		case "activation":
+			statuses[name] = "activated"
+		case "deactivation":
+			statuses[name] = "deactivated"
+		}
+	}
+
+	writeList(w, r, features)
+}
+
+func (s *Server) switchHostFeature(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input hostFeatureInput
+		if r.ContentLength > 0 && !decodeBody(w, r, &input) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id, name := r.PathValue("id"), r.PathValue("name")
+		if _, ok := s.objects[KindDedicatedServer][id]; !ok {
+			writeNotFound(w, "Dedicated server")
+			return
+		}
+		if !slices.Contains(hostFeatures, name) {
+			writeNotFound(w, "Feature")
+			return
+		}
+
+		if status == "activation" {
+			switch {
+			case name == "host_rescue_mode" && len(input.AuthMethods) == 0:
+				writeValidationError(w, "auth_methods", "can't be blank")
+				return
+			case name == "host_rescue_mode" && slices.Contains(input.AuthMethods, "ssh_key") && len(input.SSHKeyFingerprints) == 0:
+				writeValidationError(w, "ssh_key_fingerprints", "can't be blank")
+				return
+			case name == "private_ipxe_boot" && input.IPXEConfig == "":
+				writeValidationError(w, "ipxe_config", "can't be blank")
+				return
+			}
+		}
+
+		statuses := s.hostFeatureStatuses(id)
+		statuses[name] = status
+
+		writeJSON(w, http.StatusAccepted, Object{"name": name, "status": status})
+	}
+}
@@ -0,0 +1,167 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type hostNetworkInput struct {
+	Mask               int    `json:"mask"`
+	DistributionMethod string `json:"distribution_method"`
+}
+
+func (s *Server) registerHostNetworkRoutes(mux *http.ServeMux) {
+	const networks = "/hosts/dedicated_servers/{id}/networks"
+
+	mux.HandleFunc("GET "+networks, s.listHostNetworks)
+	mux.HandleFunc("GET "+networks+"/{network_id}", s.getHostNetwork)
+	mux.HandleFunc("POST "+networks+"/public_ipv4", s.addHostNetwork("ipv4", "public"))
+	mux.HandleFunc("POST "+networks+"/private_ipv4", s.addHostNetwork("ipv4", "private"))
+	mux.HandleFunc("POST "+networks+"/public_ipv6", s.addHostNetwork("ipv6", "public"))
+	mux.HandleFunc("DELETE "+networks+"/{network_id}", s.deleteHostNetwork)
+}
+
+// newHostNetwork returns a network, the pending network becomes active on the next read
+func (s *Server) newHostNetwork(family, interfaceType, cidr, gateway, distributionMethod string, additional bool) Object {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	status := "active"
+	if additional {
+		status = "pending"
+	}
+
+	return Object{
+		"id":                  s.newID(),
+		"title":               nil,
+		"status":              status,
+		"cidr":                cidr,
+		"gateway":             gateway,
+		"family":              family,
+		"interface_type":      interfaceType,
+		"distribution_method": distributionMethod,
+		"additional":          additional,
+		"created_at":          now,
+		"updated_at":          now,
+	}
+}
+
+// hostNetworks returns networks of the dedicated server, pending networks are activated by the read
+func (s *Server) hostNetworks(w http.ResponseWriter, id string) ([]Object, bool) {
+	if _, ok := s.objects[KindDedicatedServer][id]; !ok {
+		writeNotFound(w, "Dedicated server")
+		return nil, false
+	}
+
+	networks := make([]Object, 0, len(s.networks[id]))
+	for _, network := range s.networks[id] {
+		networks = append(networks, copyObject(network))
+		if network["status"] == "pending" {
+			network["status"] = "active"
+		}
+	}
+
+	return networks, true
+}
+
+func (s *Server) listHostNetworks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	networks, ok := s.hostNetworks(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	writeList(w, r, networks)
+}
+
+func (s *Server) getHostNetwork(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	networks, ok := s.hostNetworks(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	for _, network := range networks {
+		if network["id"] == r.PathValue("network_id") {
+			writeJSON(w, http.StatusOK, network)
+			return
+		}
+	}
+
+	writeNotFound(w, "Network")
+}
+
+func (s *Server) addHostNetwork(family, interfaceType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input hostNetworkInput
+		if family == "ipv4" && !decodeBody(w, r, &input) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if _, ok := s.objects[KindDedicatedServer][id]; !ok {
+			writeNotFound(w, "Dedicated server")
+			return
+		}
+
+		s.nextNetwork++
+		var cidr, gateway string
+		switch {
+		case family == "ipv6":
+			for _, network := range s.networks[id] {
+				if network["family"] == "ipv6" {
+					writeError(w, http.StatusConflict, "CONFLICT", "IPv6 network is already activated")
+					return
+				}
+			}
+			cidr, gateway = fmt.Sprintf("2001:db8:%x::/64", s.nextNetwork), fmt.Sprintf("2001:db8:%x::1", s.nextNetwork)
+		case input.Mask < 24 || input.Mask > 32:
+			writeValidationError(w, "mask", "is invalid")
+			return
+		case interfaceType == "public":
+			cidr, gateway = fmt.Sprintf("203.0.%d.0/%d", s.nextNetwork, input.Mask), fmt.Sprintf("203.0.%d.1", s.nextNetwork)
+		default:
+			cidr, gateway = fmt.Sprintf("10.2.%d.0/%d", s.nextNetwork, input.Mask), fmt.Sprintf("10.2.%d.1", s.nextNetwork)
+		}
+
+		distributionMethod := input.DistributionMethod
+		if distributionMethod == "" {
+			distributionMethod = "gateway"
+		}
+
+		network := s.newHostNetwork(family, interfaceType, cidr, gateway, distributionMethod, true)
+		s.networks[id] = append(s.networks[id], network)
+
+		writeJSON(w, http.StatusCreated, copyObject(network))
+	}
+}
+
+func (s *Server) deleteHostNetwork(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	for i, network := range s.networks[id] {
+		if network["id"] != r.PathValue("network_id") {
+			continue
+		}
+		if network["additional"] != true {
+			writeError(w, http.StatusConflict, "CONFLICT", "Only additional networks can be deleted")
+			return
+		}
+
+		s.networks[id] = append(s.networks[id][:i], s.networks[id][i+1:]...)
+		network["status"] = "removing"
+		writeJSON(w, http.StatusAccepted, copyObject(network))
+		return
+	}
+
+	writeNotFound(w, "Network")
+}
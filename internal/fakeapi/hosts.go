@@ -0,0 +1,583 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type hostInput struct {
+	Hostname             string            `json:"hostname"`
+	PublicIPv4NetworkID  *string           `json:"public_ipv4_network_id"`
+	PrivateIPv4NetworkID *string           `json:"private_ipv4_network_id"`
+	Labels               map[string]string `json:"labels"`
+}
+
+// hostnamePattern matches hostnames according to RFC 1123
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// validateHostnames checks hostnames of the hosts, the validation error points at the host index
+func validateHostnames(w http.ResponseWriter, hosts []hostInput) bool {
+	errors := make(map[string]interface{})
+	var fields []string
+	for i, host := range hosts {
+		if !hostnamePattern.MatchString(host.Hostname) {
+			field := fmt.Sprintf("hosts.%d.hostname", i)
+			errors[field] = []string{"is invalid"}
+			fields = append(fields, field+" is invalid")
+		}
+	}
+
+	if len(errors) == 0 {
+		return true
+	}
+
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+		"code":    "UNPROCESSABLE_ENTITY",
+		"message": fmt.Sprintf("Validation failed: %s", strings.Join(fields, ", ")),
+		"errors":  errors,
+	})
+
+	return false
+}
+
+type dedicatedServerCreateInput struct {
+	ServerModelID int64 `json:"server_model_id"`
+	LocationID    int64 `json:"location_id"`
+	RAMSize       int   `json:"ram_size"`
+	UplinkModels  struct {
+		Public *struct {
+			ID               int64 `json:"id"`
+			BandwidthModelID int64 `json:"bandwidth_model_id"`
+		} `json:"public"`
+		Private struct {
+			ID int64 `json:"id"`
+		} `json:"private"`
+	} `json:"uplink_models"`
+	Drives struct {
+		Slots []struct {
+			Position     int    `json:"position"`
+			DriveModelID *int64 `json:"drive_model_id"`
+		} `json:"slots"`
+		Layout []interface{} `json:"layout"`
+	} `json:"drives"`
+	Features           []string    `json:"features"`
+	IPv6               bool        `json:"ipv6"`
+	Hosts              []hostInput `json:"hosts"`
+	OperatingSystemID  *int64      `json:"operating_system_id"`
+	SSHKeyFingerprints []string    `json:"ssh_key_fingerprints"`
+	UserData           *string     `json:"user_data"`
+}
+
+type sbmServerCreateInput struct {
+	FlavorModelID      int64       `json:"sbm_flavor_model_id"`
+	LocationID         int64       `json:"location_id"`
+	Hosts              []hostInput `json:"hosts"`
+	OperatingSystemID  *int64      `json:"operating_system_id"`
+	SSHKeyFingerprints []string    `json:"ssh_key_fingerprints"`
+	UserData           *string     `json:"user_data"`
+}
+
+type dedicatedServerReinstallInput struct {
+	Hostname          string `json:"hostname"`
+	OperatingSystemID *int64 `json:"operating_system_id"`
+	Drives            struct {
+		Layout []interface{} `json:"layout"`
+	} `json:"drives"`
+	SSHKeyFingerprints []string `json:"ssh_key_fingerprints"`
+}
+
+type hostUpdateInput struct {
+	Title  *string           `json:"title"`
+	Labels map[string]string `json:"labels"`
+}
+
+func (s *Server) registerHostRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /hosts", s.listHosts)
+
+	mux.HandleFunc("POST /hosts/dedicated_servers", s.createDedicatedServers)
+	mux.HandleFunc("GET /hosts/dedicated_servers/{id}", s.getHost(KindDedicatedServer, "Dedicated server"))
+	mux.HandleFunc("PUT /hosts/dedicated_servers/{id}", s.updateHost(KindDedicatedServer, "Dedicated server"))
+	mux.HandleFunc("POST /hosts/dedicated_servers/{id}/schedule_release", s.scheduleReleaseDedicatedServer)
+	mux.HandleFunc("POST /hosts/dedicated_servers/{id}/abort_release", s.abortReleaseDedicatedServer)
+	mux.HandleFunc("POST /hosts/dedicated_servers/{id}/reinstall", s.reinstallDedicatedServer)
+	mux.HandleFunc("GET /hosts/dedicated_servers/{id}/drive_slots", s.listDriveSlots)
+	mux.HandleFunc("GET /hosts/dedicated_servers/{id}/layouts", s.listLayouts)
+	mux.HandleFunc("POST /hosts/dedicated_servers/{id}/power_on", s.powerHost(KindDedicatedServer, "Dedicated server", "powering_on"))
+	mux.HandleFunc("POST /hosts/dedicated_servers/{id}/power_off", s.powerHost(KindDedicatedServer, "Dedicated server", "powering_off"))
+
+	mux.HandleFunc("POST /hosts/sbm_servers", s.createSBMServers)
+	mux.HandleFunc("GET /hosts/sbm_servers/{id}", s.getHost(KindSBMServer, "SBM server"))
+	mux.HandleFunc("PUT /hosts/sbm_servers/{id}", s.updateHost(KindSBMServer, "SBM server"))
+	mux.HandleFunc("DELETE /hosts/sbm_servers/{id}", s.releaseSBMServer)
+	mux.HandleFunc("POST /hosts/sbm_servers/{id}/power_on", s.powerHost(KindSBMServer, "SBM server", "powering_on"))
+	mux.HandleFunc("POST /hosts/sbm_servers/{id}/power_off", s.powerHost(KindSBMServer, "SBM server", "powering_off"))
+}
+
+// matchLabelSelector reports whether labels match the "key=value,key2=value2" selector
+func matchLabelSelector(selector string, labels interface{}) bool {
+	if selector == "" {
+		return true
+	}
+
+	values, _ := labels.(map[string]interface{})
+	for _, requirement := range strings.Split(selector, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(requirement), "=")
+		if v, ok := values[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchListQuery reports whether the object matches the search_pattern by the name field and the label_selector of the query
+func matchListQuery(query url.Values, object Object, nameField string) bool {
+	if searchPattern := query.Get("search_pattern"); searchPattern != "" && !strings.Contains(fmt.Sprint(object[nameField]), searchPattern) {
+		return false
+	}
+
+	return matchLabelSelector(query.Get("label_selector"), copyObject(object)["labels"])
+}
+
+func (s *Server) listHosts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+	filter := func(object Object) bool {
+		if locationID := query.Get("location_id"); locationID != "" && fmt.Sprint(object["location_id"]) != locationID {
+			return false
+		}
+		if rackID := query.Get("rack_id"); rackID != "" && fmt.Sprint(object["rack_id"]) != rackID {
+			return false
+		}
+		return matchListQuery(query, object, "title")
+	}
+
+	hosts := []Object{}
+	if t := query.Get("type"); t == "" || t == KindDedicatedServer {
+		hosts = append(hosts, s.list(KindDedicatedServer, filter)...)
+	}
+	if t := query.Get("type"); t == "" || t == KindSBMServer {
+		hosts = append(hosts, s.list(KindSBMServer, filter)...)
+	}
+
+	writeList(w, r, hosts)
+}
+
+func (s *Server) getHost(kind, what string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		host, ok := s.read(kind, r.PathValue("id"))
+		if !ok {
+			writeNotFound(w, what)
+			return
+		}
+
+		if powerStatus, ok := powerTransitions[fmt.Sprint(host["power_status"])]; ok {
+			s.update(kind, r.PathValue("id"), Object{"power_status": powerStatus})
+		}
+
+		writeJSON(w, http.StatusOK, host)
+	}
+}
+
+func (s *Server) updateHost(kind, what string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input hostUpdateInput
+		if !decodeBody(w, r, &input) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		fields := Object{}
+		if input.Title != nil {
+			fields["title"] = *input.Title
+		}
+		if input.Labels != nil {
+			fields["labels"] = input.Labels
+		}
+
+		host, ok := s.update(kind, r.PathValue("id"), fields)
+		if !ok {
+			writeNotFound(w, what)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, host)
+	}
+}
+
+// newHost returns a host object with the common fields set
+func (s *Server) newHost(kind string, location Object, host hostInput, configuration string, details Object) Object {
+	id := s.newID()
+	labels := host.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	return Object{
+		"id":                    id,
+		"title":                 host.Hostname,
+		"type":                  kind,
+		"rack_id":               "rack1",
+		"location_id":           location["id"],
+		"location_code":         location["code"],
+		"operational_status":    "normal",
+		"power_status":          "powered_on",
+		"configuration":         configuration,
+		"private_ipv4_address":  fmt.Sprintf("10.0.%d.%d", s.nextID/250, s.nextID%250+1),
+		"public_ipv4_address":   fmt.Sprintf("198.51.%d.%d", s.nextID/250, s.nextID%250+1),
+		"oob_ipv4_address":      nil,
+		"lease_start_at":        time.Now().UTC().Format("2006-01-02"),
+		"scheduled_release_at":  nil,
+		"configuration_details": copyObject(details),
+		"labels":                labels,
+	}
+}
+
+func (s *Server) createDedicatedServers(w http.ResponseWriter, r *http.Request) {
+	var input dedicatedServerCreateInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.catalog
+
+	location, ok := findByID(c.locations, input.LocationID)
+	if !ok {
+		writeValidationError(w, "location_id", "is invalid")
+		return
+	}
+	serverModel, ok := findByID(c.serverModels, input.ServerModelID)
+	if !ok {
+		writeValidationError(w, "server_model_id", "is invalid")
+		return
+	}
+	if len(input.Hosts) == 0 {
+		writeValidationError(w, "hosts", "can't be blank")
+		return
+	}
+	if !validateHostnames(w, input.Hosts) {
+		return
+	}
+
+	details := Object{
+		"ram_size":                   input.RAMSize,
+		"server_model_id":            serverModel["id"],
+		"server_model_name":          serverModel["name"],
+		"bandwidth_id":               nil,
+		"bandwidth_name":             nil,
+		"public_uplink_id":           nil,
+		"public_uplink_name":         nil,
+		"operating_system_id":        nil,
+		"operating_system_full_name": nil,
+	}
+
+	privateUplink, ok := findByID(c.uplinkModels, input.UplinkModels.Private.ID)
+	if !ok {
+		writeValidationError(w, "uplink_models.private.id", "is invalid")
+		return
+	}
+	details["private_uplink_id"] = privateUplink["id"]
+	details["private_uplink_name"] = privateUplink["name"]
+
+	if public := input.UplinkModels.Public; public != nil {
+		publicUplink, ok := findByID(c.uplinkModels, public.ID)
+		if !ok {
+			writeValidationError(w, "uplink_models.public.id", "is invalid")
+			return
+		}
+		bandwidth, ok := findByID(c.bandwidth, public.BandwidthModelID)
+		if !ok {
+			writeValidationError(w, "uplink_models.public.bandwidth_model_id", "is invalid")
+			return
+		}
+		details["public_uplink_id"] = publicUplink["id"]
+		details["public_uplink_name"] = publicUplink["name"]
+		details["bandwidth_id"] = bandwidth["id"]
+		details["bandwidth_name"] = bandwidth["name"]
+	}
+
+	if input.OperatingSystemID != nil {
+		operatingSystem, ok := findByID(c.operatingSystems, *input.OperatingSystemID)
+		if !ok {
+			writeValidationError(w, "operating_system_id", "is invalid")
+			return
+		}
+		details["operating_system_id"] = operatingSystem["id"]
+		details["operating_system_full_name"] = operatingSystem["full_name"]
+	}
+
+	var slots []Object
+	for _, slot := range input.Drives.Slots {
+		if slot.DriveModelID == nil {
+			continue
+		}
+		driveModel, ok := findByID(c.driveModels, *slot.DriveModelID)
+		if !ok {
+			writeValidationError(w, "drives.slots.drive_model_id", "is invalid")
+			return
+		}
+		slots = append(slots, Object{
+			"position":    slot.Position,
+			"interface":   driveModel["interface"],
+			"form_factor": driveModel["form_factor"],
+			"drive_model": driveModel,
+		})
+	}
+
+	servers := []Object{}
+	for _, host := range input.Hosts {
+		server := s.create(KindDedicatedServer, s.newHost(KindDedicatedServer, location, host, fmt.Sprint(serverModel["name"]), details))
+		s.driveSlots[fmt.Sprint(server["id"])] = slots
+		s.layouts[fmt.Sprint(server["id"])] = input.Drives.Layout
+		s.networks[fmt.Sprint(server["id"])] = []Object{
+			s.newHostNetwork("ipv4", "public", fmt.Sprintf("%s/32", server["public_ipv4_address"]), "", "gateway", false),
+			s.newHostNetwork("ipv4", "private", fmt.Sprintf("%s/32", server["private_ipv4_address"]), "", "gateway", false),
+		}
+		servers = append(servers, server)
+	}
+
+	writeJSON(w, http.StatusCreated, servers)
+}
+
+func (s *Server) scheduleReleaseDedicatedServer(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ReleaseAfter string `json:"release_after"`
+	}
+	if r.ContentLength != 0 && !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	releaseAt := time.Now().UTC().Add(24 * time.Hour).Format(time.RFC3339)
+	if input.ReleaseAfter != "" {
+		releaseAt = input.ReleaseAfter
+	}
+
+	server, ok := s.update(KindDedicatedServer, r.PathValue("id"), Object{"scheduled_release_at": releaseAt})
+	if !ok {
+		writeNotFound(w, "Dedicated server")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, server)
+}
+
+func (s *Server) abortReleaseDedicatedServer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.objects[KindDedicatedServer][r.PathValue("id")]
+	if !ok {
+		writeNotFound(w, "Dedicated server")
+		return
+	}
+
+	if e.object["scheduled_release_at"] == nil {
+		writeError(w, http.StatusConflict, "CONFLICT", "Dedicated server isn't scheduled for release")
+		return
+	}
+
+	server, _ := s.update(KindDedicatedServer, r.PathValue("id"), Object{"scheduled_release_at": nil})
+
+	writeJSON(w, http.StatusOK, server)
+}
+
+// reinstallDedicatedServer replaces the operating system of the server,
+// the server passes through the scripted statuses again while it's reinstalled
+func (s *Server) reinstallDedicatedServer(w http.ResponseWriter, r *http.Request) {
+	var input dedicatedServerReinstallInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	e, ok := s.objects[KindDedicatedServer][id]
+	if !ok {
+		writeNotFound(w, "Dedicated server")
+		return
+	}
+	if input.Hostname == "" {
+		writeValidationError(w, "hostname", "can't be blank")
+		return
+	}
+
+	details := copyObject(copyObject(e.object)["configuration_details"].(map[string]interface{}))
+	details["operating_system_id"] = nil
+	details["operating_system_full_name"] = nil
+
+	if input.OperatingSystemID != nil {
+		operatingSystem, ok := findByID(s.catalog.operatingSystems, *input.OperatingSystemID)
+		if !ok {
+			writeValidationError(w, "operating_system_id", "is invalid")
+			return
+		}
+		details["operating_system_id"] = operatingSystem["id"]
+		details["operating_system_full_name"] = operatingSystem["full_name"]
+	}
+
+	s.update(KindDedicatedServer, id, Object{
+		"title":                 input.Hostname,
+		"configuration_details": details,
+	})
+	s.layouts[id] = input.Drives.Layout
+	s.restartTransitions(KindDedicatedServer, id)
+
+	server, _ := s.read(KindDedicatedServer, id)
+
+	writeJSON(w, http.StatusAccepted, server)
+}
+
+// powerTransitions map transitional power statuses to the statuses the next read of the host finishes them with
+var powerTransitions = map[string]string{
+	"powering_on":  "powered_on",
+	"powering_off": "powered_off",
+}
+
+// powerHost starts switching the power of the host, the transition is finished by the next read
+func (s *Server) powerHost(kind, what, powerStatus string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		server, ok := s.update(kind, r.PathValue("id"), Object{"power_status": powerStatus})
+		if !ok {
+			writeNotFound(w, what)
+			return
+		}
+
+		writeJSON(w, http.StatusAccepted, server)
+	}
+}
+
+// listLayouts returns the drive layouts the server was installed with
+func (s *Server) listLayouts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.objects[KindDedicatedServer][id]; !ok {
+		writeNotFound(w, "Dedicated server")
+		return
+	}
+
+	layouts := s.layouts[id]
+	if layouts == nil {
+		layouts = []interface{}{}
+	}
+
+	writeList(w, r, layouts)
+}
+
+func (s *Server) listDriveSlots(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.objects[KindDedicatedServer][id]; !ok {
+		writeNotFound(w, "Dedicated server")
+		return
+	}
+
+	slots := s.driveSlots[id]
+	if slots == nil {
+		slots = []Object{}
+	}
+
+	writeList(w, r, slots)
+}
+
+func (s *Server) createSBMServers(w http.ResponseWriter, r *http.Request) {
+	var input sbmServerCreateInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.catalog
+
+	location, ok := findByID(c.locations, input.LocationID)
+	if !ok {
+		writeValidationError(w, "location_id", "is invalid")
+		return
+	}
+	flavor, ok := findByID(c.sbmFlavors, input.FlavorModelID)
+	if !ok {
+		writeValidationError(w, "sbm_flavor_model_id", "is invalid")
+		return
+	}
+	if len(input.Hosts) == 0 {
+		writeValidationError(w, "hosts", "can't be blank")
+		return
+	}
+	if !validateHostnames(w, input.Hosts) {
+		return
+	}
+
+	details := Object{
+		"ram_size":                   flavor["ram_size"],
+		"server_model_id":            nil,
+		"server_model_name":          nil,
+		"bandwidth_id":               flavor["bandwidth_id"],
+		"bandwidth_name":             flavor["bandwidth_name"],
+		"private_uplink_id":          flavor["private_uplink_model_id"],
+		"private_uplink_name":        flavor["private_uplink_model_name"],
+		"public_uplink_id":           flavor["public_uplink_model_id"],
+		"public_uplink_name":         flavor["public_uplink_model_name"],
+		"operating_system_id":        nil,
+		"operating_system_full_name": nil,
+		"sbm_flavor_model_id":        flavor["id"],
+		"sbm_flavor_model_name":      flavor["name"],
+	}
+
+	if input.OperatingSystemID != nil {
+		operatingSystem, ok := findByID(c.sbmOperatingSystems, *input.OperatingSystemID)
+		if !ok {
+			writeValidationError(w, "operating_system_id", "is invalid")
+			return
+		}
+		details["operating_system_id"] = operatingSystem["id"]
+		details["operating_system_full_name"] = operatingSystem["full_name"]
+	}
+
+	servers := []Object{}
+	for _, host := range input.Hosts {
+		servers = append(servers, s.create(KindSBMServer, s.newHost(KindSBMServer, location, host, fmt.Sprint(flavor["name"]), details)))
+	}
+
+	writeJSON(w, http.StatusCreated, servers)
+}
+
+func (s *Server) releaseSBMServer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	server, ok := s.update(KindSBMServer, r.PathValue("id"), Object{"status": "released"})
+	if !ok {
+		writeNotFound(w, "SBM server")
+		return
+	}
+	s.delete(KindSBMServer, r.PathValue("id"))
+
+	writeJSON(w, http.StatusOK, server)
+}
@@ -0,0 +1,343 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type l2SegmentMemberInput struct {
+	ID   string `json:"id"`
+	Mode string `json:"mode"`
+}
+
+type l2SegmentCreateInput struct {
+	Name            *string                `json:"name"`
+	Type            string                 `json:"type"`
+	LocationGroupID int64                  `json:"location_group_id"`
+	Members         []l2SegmentMemberInput `json:"members"`
+	Labels          map[string]string      `json:"labels"`
+}
+
+type l2SegmentUpdateInput struct {
+	Name    *string                `json:"name"`
+	Members []l2SegmentMemberInput `json:"members"`
+	Labels  map[string]string      `json:"labels"`
+}
+
+type subnetworkInput struct {
+	Title *string `json:"title"`
+	CIDR  *string `json:"cidr"`
+	Mask  *int    `json:"mask"`
+}
+
+func (s *Server) registerNetworkRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /l2_segments", s.listL2Segments)
+	mux.HandleFunc("POST /l2_segments", s.createL2Segment)
+	mux.HandleFunc("GET /l2_segments/{id}", s.getL2Segment)
+	mux.HandleFunc("PUT /l2_segments/{id}", s.updateL2Segment)
+	mux.HandleFunc("DELETE /l2_segments/{id}", s.deleteL2Segment)
+	mux.HandleFunc("GET /l2_segments/{id}/members", s.listL2SegmentMembers)
+
+	mux.HandleFunc("GET /networks/pools", s.listNetworkPools)
+	mux.HandleFunc("GET /networks/pools/{pool_id}", s.getNetworkPool)
+	mux.HandleFunc("GET /networks/pools/{pool_id}/subnetworks", s.listSubnetworks)
+	mux.HandleFunc("POST /networks/pools/{pool_id}/subnetworks", s.createSubnetwork)
+	mux.HandleFunc("GET /networks/pools/{pool_id}/subnetworks/{id}", s.getSubnetwork)
+	mux.HandleFunc("PUT /networks/pools/{pool_id}/subnetworks/{id}", s.updateSubnetwork)
+	mux.HandleFunc("DELETE /networks/pools/{pool_id}/subnetworks/{id}", s.deleteSubnetwork)
+}
+
+// l2SegmentMembers resolves members input to hosts, it writes the error response on failure
+func (s *Server) l2SegmentMembers(w http.ResponseWriter, members []l2SegmentMemberInput) ([]Object, bool) {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	result := []Object{}
+	for i, member := range members {
+		host, ok := s.objects[KindDedicatedServer][member.ID]
+		if !ok {
+			writeValidationError(w, fmt.Sprintf("members.%d.id", i), "is invalid")
+			return nil, false
+		}
+
+		result = append(result, Object{
+			"id":         member.ID,
+			"title":      host.object["title"],
+			"mode":       member.Mode,
+			"vlan":       nil,
+			"status":     "active",
+			"labels":     host.object["labels"],
+			"created_at": now,
+			"updated_at": now,
+		})
+	}
+
+	return result, true
+}
+
+func (s *Server) listL2Segments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+
+	writeList(w, r, s.list(KindL2Segment, func(object Object) bool {
+		return matchListQuery(query, object, "name")
+	}))
+}
+
+func (s *Server) createL2Segment(w http.ResponseWriter, r *http.Request) {
+	var input l2SegmentCreateInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	locationGroup, ok := findByID(s.catalog.locationGroups, input.LocationGroupID)
+	if !ok {
+		writeValidationError(w, "location_group_id", "is invalid")
+		return
+	}
+
+	members, ok := s.l2SegmentMembers(w, input.Members)
+	if !ok {
+		return
+	}
+
+	id := s.newID()
+	name := id
+	if input.Name != nil {
+		name = *input.Name
+	}
+
+	labels := input.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	segment := s.create(KindL2Segment, Object{
+		"id":                  id,
+		"name":                name,
+		"type":                input.Type,
+		"location_group_id":   locationGroup["id"],
+		"location_group_code": locationGroup["code"],
+		"labels":              labels,
+	})
+	s.l2Members[id] = members
+
+	writeJSON(w, http.StatusCreated, segment)
+}
+
+func (s *Server) getL2Segment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segment, ok := s.read(KindL2Segment, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w, "L2 segment")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, segment)
+}
+
+func (s *Server) updateL2Segment(w http.ResponseWriter, r *http.Request) {
+	var input l2SegmentUpdateInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.objects[KindL2Segment][id]; !ok {
+		writeNotFound(w, "L2 segment")
+		return
+	}
+
+	fields := Object{}
+	if input.Name != nil {
+		fields["name"] = *input.Name
+	}
+	if input.Labels != nil {
+		fields["labels"] = input.Labels
+	}
+
+	if input.Members != nil {
+		members, ok := s.l2SegmentMembers(w, input.Members)
+		if !ok {
+			return
+		}
+		s.l2Members[id] = members
+
+		// members change is processed asynchronously
+		s.restartTransitions(KindL2Segment, id)
+	}
+
+	segment, _ := s.update(KindL2Segment, id, fields)
+
+	writeJSON(w, http.StatusOK, segment)
+}
+
+func (s *Server) deleteL2Segment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.delete(KindL2Segment, r.PathValue("id")) {
+		writeNotFound(w, "L2 segment")
+		return
+	}
+	delete(s.l2Members, r.PathValue("id"))
+
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) listL2SegmentMembers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.objects[KindL2Segment][id]; !ok {
+		writeNotFound(w, "L2 segment")
+		return
+	}
+
+	members := s.l2Members[id]
+	if members == nil {
+		members = []Object{}
+	}
+
+	writeList(w, r, members)
+}
+
+func (s *Server) listNetworkPools(w http.ResponseWriter, r *http.Request) {
+	writeList(w, r, s.catalog.networkPools)
+}
+
+func (s *Server) getNetworkPool(w http.ResponseWriter, r *http.Request) {
+	pool, ok := findByStringID(s.catalog.networkPools, r.PathValue("pool_id"))
+	if !ok {
+		writeNotFound(w, "Network pool")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pool)
+}
+
+func (s *Server) listSubnetworks(w http.ResponseWriter, r *http.Request) {
+	poolID := r.PathValue("pool_id")
+	if _, ok := findByStringID(s.catalog.networkPools, poolID); !ok {
+		writeNotFound(w, "Network pool")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeList(w, r, s.list(KindSubnetwork, func(object Object) bool {
+		return object["network_pool_id"] == poolID
+	}))
+}
+
+func (s *Server) createSubnetwork(w http.ResponseWriter, r *http.Request) {
+	poolID := r.PathValue("pool_id")
+	if _, ok := findByStringID(s.catalog.networkPools, poolID); !ok {
+		writeNotFound(w, "Network pool")
+		return
+	}
+
+	var input subnetworkInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cidr string
+	switch {
+	case input.CIDR != nil:
+		cidr = *input.CIDR
+	case input.Mask != nil:
+		cidr = fmt.Sprintf("10.0.%d.0/%d", len(s.objects[KindSubnetwork])+1, *input.Mask)
+	default:
+		writeValidationError(w, "cidr", "or mask must be specified")
+		return
+	}
+
+	subnetwork := s.create(KindSubnetwork, Object{
+		"network_pool_id": poolID,
+		"title":           input.Title,
+		"cidr":            cidr,
+		"attached":        false,
+		"interface_type":  "private",
+	})
+
+	writeJSON(w, http.StatusCreated, subnetwork)
+}
+
+// subnetwork returns the subnetwork id if it belongs to the pool from the path
+func (s *Server) subnetwork(r *http.Request) (string, bool) {
+	id := r.PathValue("id")
+	e, ok := s.objects[KindSubnetwork][id]
+	if !ok || e.object["network_pool_id"] != r.PathValue("pool_id") {
+		return "", false
+	}
+
+	return id, true
+}
+
+func (s *Server) getSubnetwork(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.subnetwork(r)
+	if !ok {
+		writeNotFound(w, "Subnetwork")
+		return
+	}
+
+	subnetwork, _ := s.read(KindSubnetwork, id)
+	writeJSON(w, http.StatusOK, subnetwork)
+}
+
+func (s *Server) updateSubnetwork(w http.ResponseWriter, r *http.Request) {
+	var input subnetworkInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.subnetwork(r)
+	if !ok {
+		writeNotFound(w, "Subnetwork")
+		return
+	}
+
+	fields := Object{}
+	if input.Title != nil {
+		fields["title"] = *input.Title
+	}
+
+	subnetwork, _ := s.update(KindSubnetwork, id, fields)
+	writeJSON(w, http.StatusOK, subnetwork)
+}
+
+func (s *Server) deleteSubnetwork(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.subnetwork(r)
+	if !ok {
+		writeNotFound(w, "Subnetwork")
+		return
+	}
+
+	s.delete(KindSubnetwork, id)
+	writeJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,129 @@
+package fakeapi
+
+import (
+	"net"
+	"net/http"
+)
+
+type ptrRecordInput struct {
+	IP       string `json:"ip"`
+	Domain   string `json:"domain"`
+	TTL      *int   `json:"ttl"`
+	Priority *int   `json:"priority"`
+}
+
+// ptrRecordAddresses are the address fields of the parent object a PTR record can point at
+var ptrRecordAddresses = []string{"public_ipv4_address", "private_ipv4_address", "local_ipv4_address", "public_ipv6_address"}
+
+func (s *Server) registerPTRRecordRoutes(mux *http.ServeMux) {
+	parents := []struct {
+		path string
+		kind string
+		what string
+	}{
+		{"/hosts/dedicated_servers/{id}", KindDedicatedServer, "Dedicated server"},
+		{"/hosts/sbm_servers/{id}", KindSBMServer, "SBM server"},
+		{"/cloud_computing/instances/{id}", KindCloudInstance, "Cloud computing instance"},
+	}
+
+	for _, p := range parents {
+		mux.HandleFunc("GET "+p.path+"/ptr_records", s.listPTRRecords(p.kind, p.what))
+		mux.HandleFunc("POST "+p.path+"/ptr_records", s.createPTRRecord(p.kind, p.what))
+		mux.HandleFunc("DELETE "+p.path+"/ptr_records/{ptr_id}", s.deletePTRRecord(p.kind, p.what))
+	}
+}
+
+func (s *Server) listPTRRecords(kind, what string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if _, ok := s.objects[kind][id]; !ok {
+			writeNotFound(w, what)
+			return
+		}
+
+		records := append([]Object{}, s.ptrRecords[kind+"/"+id]...)
+		writeList(w, r, records)
+	}
+}
+
+func (s *Server) createPTRRecord(kind, what string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input ptrRecordInput
+		if !decodeBody(w, r, &input) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		e, ok := s.objects[kind][id]
+		if !ok {
+			writeNotFound(w, what)
+			return
+		}
+
+		assigned := false
+		for _, field := range ptrRecordAddresses {
+			if address, ok := e.object[field].(string); ok && address == input.IP {
+				assigned = true
+			}
+		}
+		// addresses of the dedicated server networks can be pointed at as well
+		for _, network := range s.networks[id] {
+			_, ipNet, err := net.ParseCIDR(network["cidr"].(string))
+			if err == nil && ipNet.Contains(net.ParseIP(input.IP)) {
+				assigned = true
+			}
+		}
+		if !assigned {
+			writeValidationError(w, "ip", "is invalid")
+			return
+		}
+		if input.Domain == "" {
+			writeValidationError(w, "domain", "can't be blank")
+			return
+		}
+
+		ttl, priority := 60, 0
+		if input.TTL != nil {
+			ttl = *input.TTL
+		}
+		if input.Priority != nil {
+			priority = *input.Priority
+		}
+
+		record := Object{
+			"id":       s.newID(),
+			"ip":       input.IP,
+			"domain":   input.Domain,
+			"ttl":      ttl,
+			"priority": priority,
+		}
+		s.ptrRecords[kind+"/"+id] = append(s.ptrRecords[kind+"/"+id], record)
+
+		writeJSON(w, http.StatusCreated, record)
+	}
+}
+
+func (s *Server) deletePTRRecord(kind, what string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		key := kind + "/" + r.PathValue("id")
+		records := s.ptrRecords[key]
+		for i, record := range records {
+			if record["id"] == r.PathValue("ptr_id") {
+				s.ptrRecords[key] = append(records[:i], records[i+1:]...)
+				writeJSON(w, http.StatusNoContent, nil)
+				return
+			}
+		}
+
+		writeNotFound(w, "PTR record")
+	}
+}
@@ -0,0 +1,173 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type rbsVolumeInput struct {
+	Name       *string           `json:"name"`
+	Size       *int64            `json:"size"`
+	LocationID int64             `json:"location_id"`
+	FlavorID   int64             `json:"flavor_id"`
+	Labels     map[string]string `json:"labels"`
+}
+
+func (s *Server) registerRBSRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /remote_block_storage/volumes", s.listRBSVolumes)
+	mux.HandleFunc("POST /remote_block_storage/volumes", s.createRBSVolume)
+	mux.HandleFunc("GET /remote_block_storage/volumes/{id}", s.getRBSVolume)
+	mux.HandleFunc("PUT /remote_block_storage/volumes/{id}", s.updateRBSVolume)
+	mux.HandleFunc("DELETE /remote_block_storage/volumes/{id}", s.deleteRBSVolume)
+	mux.HandleFunc("GET /remote_block_storage/volumes/{id}/credentials", s.getRBSVolumeCredentials)
+}
+
+func (s *Server) listRBSVolumes(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+
+	writeList(w, r, s.list(KindRBSVolume, func(object Object) bool {
+		if locationID := query.Get("location_id"); locationID != "" && fmt.Sprint(object["location_id"]) != locationID {
+			return false
+		}
+		return matchListQuery(query, object, "name")
+	}))
+}
+
+func (s *Server) createRBSVolume(w http.ResponseWriter, r *http.Request) {
+	var input rbsVolumeInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	location, ok := findByID(s.catalog.locations, input.LocationID)
+	if !ok {
+		writeValidationError(w, "location_id", "is invalid")
+		return
+	}
+	flavor, ok := findByID(s.catalog.rbsFlavors, input.FlavorID)
+	if !ok {
+		writeValidationError(w, "flavor_id", "is invalid")
+		return
+	}
+	if input.Name == nil || *input.Name == "" {
+		writeValidationError(w, "name", "can't be blank")
+		return
+	}
+	if input.Size == nil || *input.Size < toInt64(flavor["min_size_gb"]) {
+		writeValidationError(w, "size", fmt.Sprintf("must be greater than or equal to %v", flavor["min_size_gb"]))
+		return
+	}
+
+	labels := input.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	id := s.newID()
+	volume := s.create(KindRBSVolume, Object{
+		"id":            id,
+		"name":          *input.Name,
+		"size":          *input.Size,
+		"location_id":   location["id"],
+		"location_code": location["code"],
+		"flavor_id":     flavor["id"],
+		"flavor_name":   flavor["name"],
+		"ip_address":    fmt.Sprintf("10.2.%d.%d", s.nextID/250, s.nextID%250+1),
+		"target_iqn":    fmt.Sprintf("iqn.2020-01.com.servers:%s", id),
+		"iops":          float64(*input.Size) * flavor["iops_per_gb"].(float64),
+		"bandwidth":     float64(*input.Size) * flavor["bandwidth_per_gb"].(float64),
+		"labels":        labels,
+	})
+
+	writeJSON(w, http.StatusCreated, volume)
+}
+
+func (s *Server) getRBSVolume(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	volume, ok := s.read(KindRBSVolume, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w, "Remote block storage volume")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, volume)
+}
+
+func (s *Server) updateRBSVolume(w http.ResponseWriter, r *http.Request) {
+	var input rbsVolumeInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	e, ok := s.objects[KindRBSVolume][id]
+	if !ok {
+		writeNotFound(w, "Remote block storage volume")
+		return
+	}
+
+	fields := Object{}
+	if input.Name != nil && *input.Name != "" {
+		fields["name"] = *input.Name
+	}
+	if input.Labels != nil {
+		fields["labels"] = input.Labels
+	}
+	if input.Size != nil && *input.Size != 0 {
+		if *input.Size < toInt64(e.object["size"]) {
+			writeValidationError(w, "size", "can't be decreased")
+			return
+		}
+
+		// resize is processed asynchronously
+		fields["size"] = *input.Size
+		fields["status"] = "pending"
+		e.pending = []string{"active"}
+	}
+
+	volume, _ := s.update(KindRBSVolume, id, fields)
+
+	writeJSON(w, http.StatusOK, volume)
+}
+
+func (s *Server) deleteRBSVolume(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.delete(KindRBSVolume, r.PathValue("id")) {
+		writeNotFound(w, "Remote block storage volume")
+		return
+	}
+
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) getRBSVolumeCredentials(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.objects[KindRBSVolume][r.PathValue("id")]
+	if !ok {
+		writeNotFound(w, "Remote block storage volume")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Object{
+		"volume_id":  e.object["id"],
+		"username":   "rbs-user",
+		"password":   "rbs-password",
+		"target_iqn": e.object["target_iqn"],
+		"ip_address": e.object["ip_address"],
+	})
+}
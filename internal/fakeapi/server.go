@@ -0,0 +1,361 @@
+// Package fakeapi provides an in-memory fake of the Servers.com public API.
+// It's used to run provider tests without credentials and network access.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of the objects stored by the fake
+const (
+	KindDedicatedServer = "dedicated_server"
+	KindSBMServer       = "sbm_server"
+	KindL2Segment       = "l2_segment"
+	KindSubnetwork      = "subnetwork"
+	KindSSHKey          = "ssh_key"
+	KindCloudInstance   = "cloud_computing_instance"
+	KindRBSVolume       = "rbs_volume"
+)
+
+// defaultTransitions are the statuses objects pass through after creation.
+// The first status is set on create, every next one on a subsequent read of the object.
+var defaultTransitions = map[string][]string{
+	KindDedicatedServer: {"init", "pending", "active"},
+	KindSBMServer:       {"init", "pending", "active"},
+	KindL2Segment:       {"pending", "active"},
+	KindCloudInstance:   {"BUILDING", "ACTIVE"},
+	KindRBSVolume:       {"creating", "active"},
+}
+
+// Object represents a stored api object as it's rendered in responses
+type Object map[string]interface{}
+
+type entry struct {
+	object  Object
+	pending []string
+}
+
+// Server is a stateful fake of the Servers.com api.
+// Use URL as the provider endpoint, any non-empty token is accepted.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	nextID      int
+	objects     map[string]map[string]*entry
+	order       map[string][]string
+	transitions map[string][]string
+	requests    []string
+	catalog     *catalog
+	driveSlots  map[string][]Object
+	layouts     map[string][]interface{}
+	l2Members   map[string][]Object
+	ptrRecords  map[string][]Object
+	networks    map[string][]Object
+	features    map[string]map[string]string
+	nextNetwork int
+}
+
+// NewServer starts a new fake api server seeded with the default catalog.
+// The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		objects:     make(map[string]map[string]*entry),
+		order:       make(map[string][]string),
+		transitions: make(map[string][]string),
+		catalog:     newCatalog(),
+		driveSlots:  make(map[string][]Object),
+		layouts:     make(map[string][]interface{}),
+		l2Members:   make(map[string][]Object),
+		ptrRecords:  make(map[string][]Object),
+		networks:    make(map[string][]Object),
+		features:    make(map[string]map[string]string),
+	}
+
+	for kind, statuses := range defaultTransitions {
+		s.transitions[kind] = statuses
+	}
+
+	mux := http.NewServeMux()
+	s.registerCatalogRoutes(mux)
+	s.registerHostRoutes(mux)
+	s.registerNetworkRoutes(mux)
+	s.registerSSHKeyRoutes(mux)
+	s.registerCloudRoutes(mux)
+	s.registerRBSRoutes(mux)
+	s.registerPTRRecordRoutes(mux)
+	s.registerHostNetworkRoutes(mux)
+	s.registerHostFeatureRoutes(mux)
+
+	s.Server = httptest.NewServer(s.handler(mux))
+
+	return s
+}
+
+// SetTransitions overrides statuses which objects of the kind pass through after creation
+func (s *Server) SetTransitions(kind string, statuses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transitions[kind] = statuses
+}
+
+// Put stores the object, it can be used to seed objects created outside of terraform
+func (s *Server) Put(kind string, object Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(kind, copyObject(object), nil)
+}
+
+// Get returns a copy of the stored object
+func (s *Server) Get(kind, id string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.objects[kind][id]
+	if !ok {
+		return nil, false
+	}
+
+	return copyObject(e.object), true
+}
+
+// List returns copies of all stored objects of the kind in creation order
+func (s *Server) List(kind string) []Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var objects []Object
+	for _, id := range s.order[kind] {
+		objects = append(objects, copyObject(s.objects[kind][id].object))
+	}
+
+	return objects
+}
+
+// Requests returns handled requests in the "METHOD /path" form
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") || r.Header.Get("Authorization") == "Bearer " {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Unauthorized")
+			return
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("fake%06d", s.nextID)
+}
+
+// create stores a new object and applies the first scripted status
+func (s *Server) create(kind string, object Object) Object {
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, ok := object["id"]; !ok {
+		object["id"] = s.newID()
+	}
+	object["created_at"] = now
+	object["updated_at"] = now
+
+	var pending []string
+	if statuses := s.transitions[kind]; len(statuses) > 0 {
+		object["status"] = statuses[0]
+		pending = append(pending, statuses[1:]...)
+	}
+
+	s.put(kind, object, pending)
+
+	return copyObject(object)
+}
+
+func (s *Server) put(kind string, object Object, pending []string) {
+	id := fmt.Sprint(object["id"])
+	if s.objects[kind] == nil {
+		s.objects[kind] = make(map[string]*entry)
+	}
+	if _, ok := s.objects[kind][id]; !ok {
+		s.order[kind] = append(s.order[kind], id)
+	}
+	s.objects[kind][id] = &entry{object: object, pending: pending}
+}
+
+// read returns the object and moves it to the next scripted status
+func (s *Server) read(kind, id string) (Object, bool) {
+	e, ok := s.objects[kind][id]
+	if !ok {
+		return nil, false
+	}
+
+	object := copyObject(e.object)
+
+	if len(e.pending) > 0 {
+		e.object["status"] = e.pending[0]
+		e.pending = e.pending[1:]
+	}
+
+	return object, true
+}
+
+// restartTransitions makes the object pass through the scripted statuses again,
+// it's used for changes the api processes asynchronously
+func (s *Server) restartTransitions(kind, id string) {
+	e, ok := s.objects[kind][id]
+	if !ok {
+		return
+	}
+
+	if statuses := s.transitions[kind]; len(statuses) > 0 {
+		e.object["status"] = statuses[0]
+		e.pending = append([]string(nil), statuses[1:]...)
+	}
+}
+
+// update merges fields into the stored object
+func (s *Server) update(kind, id string, fields Object) (Object, bool) {
+	e, ok := s.objects[kind][id]
+	if !ok {
+		return nil, false
+	}
+
+	for k, v := range fields {
+		e.object[k] = v
+	}
+	e.object["updated_at"] = time.Now().UTC().Format(time.RFC3339)
+
+	return copyObject(e.object), true
+}
+
+func (s *Server) delete(kind, id string) bool {
+	if _, ok := s.objects[kind][id]; !ok {
+		return false
+	}
+
+	delete(s.objects[kind], id)
+	for i, v := range s.order[kind] {
+		if v == id {
+			s.order[kind] = append(s.order[kind][:i], s.order[kind][i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+func (s *Server) list(kind string, filter func(Object) bool) []Object {
+	objects := []Object{}
+	for _, id := range s.order[kind] {
+		object := s.objects[kind][id].object
+		if filter == nil || filter(object) {
+			objects = append(objects, copyObject(object))
+		}
+	}
+
+	return objects
+}
+
+// writeList writes a page of items, paginating the same way as the real api
+func writeList[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = 100
+	}
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+
+	if end < len(items) {
+		next := *r.URL
+		query := next.Query()
+		query.Set("page", strconv.Itoa(page+1))
+		query.Set("per_page", strconv.Itoa(perPage))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(items)))
+
+	writeJSON(w, http.StatusOK, items[start:end])
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if body != nil {
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"code":    code,
+		"message": message,
+		"errors":  map[string]interface{}{},
+	})
+}
+
+func writeValidationError(w http.ResponseWriter, field, message string) {
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+		"code":    "UNPROCESSABLE_ENTITY",
+		"message": fmt.Sprintf("Validation failed: %s %s", field, message),
+		"errors":  map[string]interface{}{field: []string{message}},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, what string) {
+	writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("%s not found", what))
+}
+
+// decodeBody decodes the json request body, it writes the error response on failure
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid request body: %s", err))
+		return false
+	}
+
+	return true
+}
+
+func copyObject(object Object) Object {
+	raw, _ := json.Marshal(object)
+
+	var out Object
+	json.Unmarshal(raw, &out)
+
+	return out
+}
+
+func pathInt(r *http.Request, name string) int64 {
+	v, _ := strconv.ParseInt(r.PathValue(name), 10, 64)
+	return v
+}
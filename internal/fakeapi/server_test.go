@@ -0,0 +1,488 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func doRequest(t *testing.T, s *Server, method, path, body string, out interface{}) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("can't decode %s %s response: %s", method, path, err)
+		}
+	}
+
+	return resp
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/locations")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+}
+
+func TestServer_DedicatedServerTransitions(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var servers []Object
+	resp := doRequest(t, s, "POST", "/hosts/dedicated_servers", `{
+		"server_model_id": 1,
+		"location_id": 1,
+		"ram_size": 32,
+		"uplink_models": {"public": {"id": 1, "bandwidth_model_id": 1}, "private": {"id": 2}},
+		"drives": {"slots": [{"position": 0, "drive_model_id": 1}]},
+		"operating_system_id": 1,
+		"hosts": [{"hostname": "node-1", "labels": {"env": "test"}}, {"hostname": "node-2"}]
+	}`, &servers)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("expected 2 servers, got %d", len(servers))
+	}
+
+	id := servers[0]["id"].(string)
+	for _, expected := range []string{"init", "pending", "active", "active"} {
+		var server Object
+		doRequest(t, s, "GET", "/hosts/dedicated_servers/"+id, "", &server)
+
+		if server["status"] != expected {
+			t.Fatalf("expected status %q, got %q", expected, server["status"])
+		}
+	}
+
+	var slots []Object
+	doRequest(t, s, "GET", "/hosts/dedicated_servers/"+id+"/drive_slots", "", &slots)
+	if len(slots) != 1 || slots[0]["drive_model"].(map[string]interface{})["name"] != "480 GB SSD SATA" {
+		t.Fatalf("unexpected drive slots: %v", slots)
+	}
+
+	var hosts []Object
+	doRequest(t, s, "GET", "/hosts?label_selector=env=test", "", &hosts)
+	if len(hosts) != 1 || hosts[0]["title"] != "node-1" {
+		t.Fatalf("expected only node-1 to match the label selector, got %v", hosts)
+	}
+
+	doRequest(t, s, "GET", "/hosts?rack_id=missing", "", &hosts)
+	if len(hosts) != 0 {
+		t.Fatalf("expected no hosts in the missing rack, got %v", hosts)
+	}
+}
+
+func TestServer_ValidationError(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var body Object
+	resp := doRequest(t, s, "POST", "/hosts/sbm_servers", `{"sbm_flavor_model_id": 42, "location_id": 1, "hosts": [{"hostname": "node"}]}`, &body)
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+	if body["code"] != "UNPROCESSABLE_ENTITY" {
+		t.Fatalf("unexpected error body: %v", body)
+	}
+}
+
+func TestServer_NotFound(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var body Object
+	resp := doRequest(t, s, "GET", "/ssh_keys/missing", "", &body)
+
+	if resp.StatusCode != http.StatusNotFound || body["code"] != "NOT_FOUND" {
+		t.Fatalf("expected not found error, got %d %v", resp.StatusCode, body)
+	}
+}
+
+func TestServer_Pagination(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var locations []Object
+	resp := doRequest(t, s, "GET", "/locations?per_page=1", "", &locations)
+
+	if len(locations) != 1 {
+		t.Fatalf("expected 1 location on the page, got %d", len(locations))
+	}
+	if link := resp.Header.Get("Link"); !strings.Contains(link, "page=2") || !strings.Contains(link, `rel="next"`) {
+		t.Fatalf("expected link to the next page, got %q", link)
+	}
+
+	resp = doRequest(t, s, "GET", "/locations?per_page=1&page=2", "", &locations)
+	if link := resp.Header.Get("Link"); link != "" {
+		t.Fatalf("expected no link on the last page, got %q", link)
+	}
+}
+
+func TestServer_SSHKeyLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var sshKey Object
+	doRequest(t, s, "POST", "/ssh_keys", `{"name": "key", "public_key": "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGB1cmRlbnRseSBmYWtlIGtleSBmb3IgdGVzdHM="}`, &sshKey)
+
+	fingerprint, _ := sshKey["fingerprint"].(string)
+	if len(fingerprint) != 47 {
+		t.Fatalf("expected md5 fingerprint, got %q", fingerprint)
+	}
+
+	doRequest(t, s, "PUT", "/ssh_keys/"+fingerprint, `{"name": "renamed"}`, &sshKey)
+	if sshKey["name"] != "renamed" {
+		t.Fatalf("expected name to be updated, got %v", sshKey["name"])
+	}
+
+	if resp := doRequest(t, s, "DELETE", "/ssh_keys/"+fingerprint, "", nil); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+	}
+	if _, ok := s.Get(KindSSHKey, fingerprint); ok {
+		t.Fatal("expected ssh key to be deleted")
+	}
+}
+
+func TestServer_RBSVolumeResize(t *testing.T) {
+	s := NewServer()
+	s.SetTransitions(KindRBSVolume, "active")
+	defer s.Close()
+
+	var volume Object
+	doRequest(t, s, "POST", "/remote_block_storage/volumes", `{"name": "vol", "size": 10, "location_id": 1, "flavor_id": 1}`, &volume)
+	if volume["status"] != "active" {
+		t.Fatalf("expected status active, got %v", volume["status"])
+	}
+
+	id := volume["id"].(string)
+	doRequest(t, s, "PUT", "/remote_block_storage/volumes/"+id, `{"size": 20}`, &volume)
+	if volume["status"] != "pending" {
+		t.Fatalf("expected status pending after resize, got %v", volume["status"])
+	}
+
+	doRequest(t, s, "GET", "/remote_block_storage/volumes/"+id, "", &volume)
+	doRequest(t, s, "GET", "/remote_block_storage/volumes/"+id, "", &volume)
+	if volume["status"] != "active" || volume["size"] != float64(20) {
+		t.Fatalf("expected resized active volume, got %v", volume)
+	}
+}
+
+func TestServer_L2SegmentMembers(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Put(KindDedicatedServer, Object{"id": "host1", "title": "node-1", "status": "active", "labels": map[string]string{}})
+
+	var segment Object
+	doRequest(t, s, "POST", "/l2_segments", `{"name": "segment", "type": "private", "location_group_id": 2, "members": [{"id": "host1", "mode": "native"}]}`, &segment)
+	if segment["status"] != "pending" {
+		t.Fatalf("expected status pending, got %v", segment["status"])
+	}
+
+	var members []Object
+	doRequest(t, s, "GET", "/l2_segments/"+segment["id"].(string)+"/members", "", &members)
+	if len(members) != 1 || members[0]["title"] != "node-1" || members[0]["mode"] != "native" {
+		t.Fatalf("unexpected members: %v", members)
+	}
+}
+
+func TestServer_InvalidHostname(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var body struct {
+		Code   string              `json:"code"`
+		Errors map[string][]string `json:"errors"`
+	}
+	resp := doRequest(t, s, "POST", "/hosts/sbm_servers", `{"sbm_flavor_model_id": 1, "location_id": 1, "operating_system_id": 2, "hosts": [{"hostname": "node-1"}, {"hostname": "node_2"}]}`, &body)
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+	if _, ok := body.Errors["hosts.1.hostname"]; !ok || len(body.Errors) != 1 {
+		t.Fatalf("expected error of the second host only, got %v", body.Errors)
+	}
+}
+
+func TestServer_DedicatedServerReinstall(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var servers []Object
+	doRequest(t, s, "POST", "/hosts/dedicated_servers", `{
+		"server_model_id": 1,
+		"location_id": 1,
+		"uplink_models": {"private": {"id": 2}},
+		"drives": {"slots": [{"position": 0, "drive_model_id": 1}]},
+		"hosts": [{"hostname": "node-1"}]
+	}`, &servers)
+
+	id := servers[0]["id"].(string)
+
+	var server Object
+	resp := doRequest(t, s, "POST", "/hosts/dedicated_servers/"+id+"/reinstall", `{
+		"hostname": "node-1",
+		"operating_system_id": 1,
+		"drives": {"layout": [{"slot_positions": [0], "partitions": [{"target": "/", "size": 1, "fill": true}]}]}
+	}`, &server)
+
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, resp.StatusCode)
+	}
+	if server["status"] != "init" {
+		t.Fatalf("expected the server to be reinstalled, got status %q", server["status"])
+	}
+	if details := server["configuration_details"].(map[string]interface{}); details["operating_system_id"] != float64(1) {
+		t.Fatalf("expected operating system to be replaced, got %v", details["operating_system_id"])
+	}
+}
+
+func TestServer_PTRRecords(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var servers []Object
+	doRequest(t, s, "POST", "/hosts/sbm_servers", `{"sbm_flavor_model_id": 1, "location_id": 1, "hosts": [{"hostname": "node-1"}]}`, &servers)
+
+	id := servers[0]["id"].(string)
+	path := "/hosts/sbm_servers/" + id + "/ptr_records"
+
+	resp := doRequest(t, s, "POST", path, `{"ip": "192.0.2.1", "domain": "node-1.example.com"}`, nil)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected unassigned ip to be rejected, got status %d", resp.StatusCode)
+	}
+
+	var record Object
+	resp = doRequest(t, s, "POST", path, `{"ip": "`+servers[0]["public_ipv4_address"].(string)+`", "domain": "node-1.example.com"}`, &record)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	resp = doRequest(t, s, "DELETE", path+"/"+record["id"].(string), "", nil)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+	}
+
+	var records []Object
+	doRequest(t, s, "GET", path, "", &records)
+	if len(records) != 0 {
+		t.Fatalf("expected no records, got %v", records)
+	}
+}
+
+func TestServer_DedicatedServerNetworks(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var servers []Object
+	doRequest(t, s, "POST", "/hosts/dedicated_servers", `{
+		"server_model_id": 1,
+		"location_id": 1,
+		"uplink_models": {"private": {"id": 2}},
+		"drives": {"slots": [{"position": 0, "drive_model_id": 1}]},
+		"hosts": [{"hostname": "node-1"}]
+	}`, &servers)
+
+	path := "/hosts/dedicated_servers/" + servers[0]["id"].(string) + "/networks"
+
+	resp := doRequest(t, s, "POST", path+"/public_ipv4", `{"mask": 8}`, nil)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected invalid mask to be rejected, got status %d", resp.StatusCode)
+	}
+
+	var network Object
+	resp = doRequest(t, s, "POST", path+"/public_ipv4", `{"mask": 29, "distribution_method": "route"}`, &network)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+	if network["status"] != "pending" {
+		t.Fatalf("expected new network to be pending, got %q", network["status"])
+	}
+
+	networkPath := path + "/" + network["id"].(string)
+
+	doRequest(t, s, "GET", networkPath, "", &network)
+	doRequest(t, s, "GET", networkPath, "", &network)
+	if network["status"] != "active" {
+		t.Fatalf("expected network to become active, got %q", network["status"])
+	}
+
+	var networks []Object
+	doRequest(t, s, "GET", path, "", &networks)
+	if len(networks) != 3 {
+		t.Fatalf("expected the initial public and private networks and the added one, got %v", networks)
+	}
+
+	resp = doRequest(t, s, "POST", "/hosts/dedicated_servers/"+servers[0]["id"].(string)+"/ptr_records", `{"ip": "`+strings.TrimSuffix(network["cidr"].(string), "0/29")+`5", "domain": "node-1.example.com"}`, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected PTR record for the network address to be created, got status %d", resp.StatusCode)
+	}
+
+	resp = doRequest(t, s, "DELETE", path+"/"+networks[0]["id"].(string), "", nil)
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected initial network deletion to be rejected, got status %d", resp.StatusCode)
+	}
+
+	resp = doRequest(t, s, "DELETE", networkPath, "", nil)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, resp.StatusCode)
+	}
+
+	resp = doRequest(t, s, "GET", networkPath, "", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected deleted network to be gone, got status %d", resp.StatusCode)
+	}
+}
+
+func TestServer_DedicatedServerFeatures(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var servers []Object
+	doRequest(t, s, "POST", "/hosts/dedicated_servers", `{
+		"server_model_id": 1,
+		"location_id": 1,
+		"uplink_models": {"private": {"id": 2}},
+		"drives": {"slots": [{"position": 0, "drive_model_id": 1}]},
+		"hosts": [{"hostname": "node-1"}]
+	}`, &servers)
+
+	path := "/hosts/dedicated_servers/" + servers[0]["id"].(string) + "/features"
+
+	resp := doRequest(t, s, "POST", path+"/host_rescue_mode/activate", `{"auth_methods": ["ssh_key"]}`, nil)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected rescue mode without ssh keys to be rejected, got status %d", resp.StatusCode)
+	}
+
+	resp = doRequest(t, s, "POST", path+"/host_rescue_mode/activate", `{"auth_methods": ["password"]}`, nil)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, resp.StatusCode)
+	}
+
+	status := func() interface{} {
+		var features []Object
+		doRequest(t, s, "GET", path, "", &features)
+		for _, feature := range features {
+			if feature["name"] == "host_rescue_mode" {
+				return feature["status"]
+			}
+		}
+		return nil
+	}
+
+	if got := status(); got != "activation" {
+		t.Fatalf("expected feature to be activating, got %v", got)
+	}
+	if got := status(); got != "activated" {
+		t.Fatalf("expected feature to be activated, got %v", got)
+	}
+}
+
+func TestServer_DedicatedServerAbortRelease(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var servers []Object
+	doRequest(t, s, "POST", "/hosts/dedicated_servers", `{
+		"server_model_id": 1,
+		"location_id": 1,
+		"uplink_models": {"private": {"id": 2}},
+		"drives": {"slots": [{"position": 0, "drive_model_id": 1}]},
+		"hosts": [{"hostname": "node-1"}]
+	}`, &servers)
+
+	path := "/hosts/dedicated_servers/" + servers[0]["id"].(string)
+
+	resp := doRequest(t, s, "POST", path+"/abort_release", "", nil)
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected abort without scheduled release to be rejected, got status %d", resp.StatusCode)
+	}
+
+	doRequest(t, s, "POST", path+"/schedule_release", `{"release_after": "2030-01-01T00:00:00Z"}`, nil)
+
+	var server Object
+	resp = doRequest(t, s, "POST", path+"/abort_release", "", &server)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if server["scheduled_release_at"] != nil {
+		t.Fatalf("expected release to be aborted, got %v", server["scheduled_release_at"])
+	}
+}
+
+func TestServer_DedicatedServerLayouts(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var servers []Object
+	doRequest(t, s, "POST", "/hosts/dedicated_servers", `{
+		"server_model_id": 1,
+		"location_id": 1,
+		"uplink_models": {"private": {"id": 2}},
+		"operating_system_id": 1,
+		"drives": {
+			"slots": [{"position": 0, "drive_model_id": 1}],
+			"layout": [{"slot_positions": [0], "partitions": [{"target": "/", "size": 1, "fs": "ext4", "fill": true}]}]
+		},
+		"hosts": [{"hostname": "node-1"}]
+	}`, &servers)
+
+	var layouts []Object
+	doRequest(t, s, "GET", "/hosts/dedicated_servers/"+servers[0]["id"].(string)+"/layouts", "", &layouts)
+
+	if len(layouts) != 1 {
+		t.Fatalf("expected the layout the server was installed with, got %v", layouts)
+	}
+	if partitions := layouts[0]["partitions"].([]interface{}); len(partitions) != 1 {
+		t.Fatalf("expected one partition, got %v", partitions)
+	}
+}
+
+func TestServer_HostPower(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var servers []Object
+	doRequest(t, s, "POST", "/hosts/sbm_servers", `{"sbm_flavor_model_id": 1, "location_id": 1, "hosts": [{"hostname": "node-1"}]}`, &servers)
+
+	path := "/hosts/sbm_servers/" + servers[0]["id"].(string)
+
+	resp := doRequest(t, s, "POST", path+"/power_off", "", nil)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, resp.StatusCode)
+	}
+
+	for _, expected := range []string{"powering_off", "powered_off"} {
+		var server Object
+		doRequest(t, s, "GET", path, "", &server)
+		if server["power_status"] != expected {
+			t.Fatalf("expected power status %s, got %v", expected, server["power_status"])
+		}
+	}
+}
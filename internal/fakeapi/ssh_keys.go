@@ -0,0 +1,145 @@
+package fakeapi
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type sshKeyInput struct {
+	Name      *string           `json:"name"`
+	PublicKey string            `json:"public_key"`
+	Labels    map[string]string `json:"labels"`
+}
+
+func (s *Server) registerSSHKeyRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /ssh_keys", s.listSSHKeys)
+	mux.HandleFunc("POST /ssh_keys", s.createSSHKey)
+	mux.HandleFunc("GET /ssh_keys/{fingerprint}", s.getSSHKey)
+	mux.HandleFunc("PUT /ssh_keys/{fingerprint}", s.updateSSHKey)
+	mux.HandleFunc("DELETE /ssh_keys/{fingerprint}", s.deleteSSHKey)
+}
+
+// sshKeyFingerprint returns md5 fingerprint of the public key in the aa:bb:... form
+func sshKeyFingerprint(publicKey string) (string, error) {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("invalid public key")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %s", err)
+	}
+
+	sum := md5.Sum(key)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+
+	return strings.Join(parts, ":"), nil
+}
+
+func (s *Server) listSSHKeys(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeList(w, r, s.list(KindSSHKey, nil))
+}
+
+func (s *Server) createSSHKey(w http.ResponseWriter, r *http.Request) {
+	var input sshKeyInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	fingerprint, err := sshKeyFingerprint(input.PublicKey)
+	if err != nil {
+		writeValidationError(w, "public_key", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[KindSSHKey][fingerprint]; ok {
+		writeError(w, http.StatusConflict, "CONFLICT", "SSH key with the same fingerprint already exists")
+		return
+	}
+
+	name := fingerprint
+	if input.Name != nil {
+		name = *input.Name
+	}
+
+	labels := input.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	sshKey := s.create(KindSSHKey, Object{
+		"id":          fingerprint,
+		"name":        name,
+		"fingerprint": fingerprint,
+		"labels":      labels,
+	})
+	delete(sshKey, "id")
+
+	writeJSON(w, http.StatusCreated, sshKey)
+}
+
+func (s *Server) getSSHKey(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sshKey, ok := s.read(KindSSHKey, r.PathValue("fingerprint"))
+	if !ok {
+		writeNotFound(w, "SSH key")
+		return
+	}
+	delete(sshKey, "id")
+
+	writeJSON(w, http.StatusOK, sshKey)
+}
+
+func (s *Server) updateSSHKey(w http.ResponseWriter, r *http.Request) {
+	var input sshKeyInput
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fields := Object{}
+	if input.Name != nil {
+		fields["name"] = *input.Name
+	}
+	if input.Labels != nil {
+		fields["labels"] = input.Labels
+	}
+
+	sshKey, ok := s.update(KindSSHKey, r.PathValue("fingerprint"), fields)
+	if !ok {
+		writeNotFound(w, "SSH key")
+		return
+	}
+	delete(sshKey, "id")
+
+	writeJSON(w, http.StatusOK, sshKey)
+}
+
+func (s *Server) deleteSSHKey(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.delete(KindSSHKey, r.PathValue("fingerprint")) {
+		writeNotFound(w, "SSH key")
+		return
+	}
+
+	writeJSON(w, http.StatusNoContent, nil)
+}
@@ -3,277 +3,279 @@ package serverscom
 import (
 	"context"
 	"fmt"
-	"sync"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	scgo "github.com/serverscom/serverscom-go-client/pkg"
+	"golang.org/x/sync/singleflight"
 )
 
-var cache *Cache
+var (
+	defaultCacheTTL      = 10 * time.Minute
+	defaultCacheCapacity = 100
+)
 
-func NewCache(cli *scgo.Client) *Cache {
-	newLru, err := lru.New(100)
-	if err != nil {
-		panic(err)
-	}
+// CacheConfig describes lifetime and size of the order options cache
+type CacheConfig struct {
+	TTL      time.Duration
+	Capacity int
+}
 
-	return &Cache{
-		client: cli,
-		lru:    newLru,
-		ctx:    context.TODO(),
+// DefaultCacheConfig returns the cache config used when the provider has no cache block
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		TTL:      defaultCacheTTL,
+		Capacity: defaultCacheCapacity,
 	}
 }
 
-type Cache struct {
-	client *scgo.Client
-	lru    *lru.Cache
-	ctx    context.Context
-
-	sync.Mutex
+func cacheSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Settings of the cache for locations and order options lookups",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"ttl": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      defaultCacheTTL.String(),
+					ValidateFunc: validateDuration,
+					Description:  "How long a cached lookup is reused, 0 disables expiration.",
+				},
+				"capacity": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      defaultCacheCapacity,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Maximum number of cached lookups, least recently used ones are evicted first.",
+				},
+			},
+		},
+	}
 }
 
-func (c *Cache) Locations() ([]scgo.Location, error) {
-	c.Lock()
-	defer c.Unlock()
+// expandCacheConfig builds CacheConfig from the provider cache block
+func expandCacheConfig(d *schema.ResourceData) (CacheConfig, error) {
+	config := DefaultCacheConfig()
 
-	val, ok := c.lru.Get("locations")
-	if ok {
-		return val.([]scgo.Location), nil
+	v, ok := d.GetOk("cache")
+	if !ok || len(v.([]interface{})) == 0 || v.([]interface{})[0] == nil {
+		return config, nil
 	}
 
-	locations, err := c.client.Locations.Collection().Collect(c.ctx)
+	cache := v.([]interface{})[0].(map[string]interface{})
+
+	ttl, err := time.ParseDuration(cache["ttl"].(string))
 	if err != nil {
-		return nil, err
+		return config, fmt.Errorf("invalid cache ttl: %s", err)
 	}
+	config.TTL = ttl
+	config.Capacity = cache["capacity"].(int)
 
-	c.lru.Add("locations", locations)
-
-	return locations, nil
+	return config, nil
 }
 
-func (c *Cache) ServerModels(locationID int64) ([]scgo.ServerModelOption, error) {
-	c.Lock()
-	defer c.Unlock()
-
-	key := fmt.Sprintf("locations/%d/server_models", locationID)
-
-	val, ok := c.lru.Get(key)
-	if ok {
-		return val.([]scgo.ServerModelOption), nil
-	}
-
-	serverModels, err := c.client.Locations.ServerModelOptions(locationID).Collect(c.ctx)
+func NewCache(cli *scgo.Client, config CacheConfig) *Cache {
+	newLru, err := lru.New(config.Capacity)
 	if err != nil {
-		return nil, err
+		panic(err)
 	}
 
-	c.lru.Add(key, serverModels)
-
-	return serverModels, nil
+	return &Cache{
+		client: cli,
+		lru:    newLru,
+		ttl:    config.TTL,
+	}
 }
 
-func (c *Cache) DriveModels(locationID int64, serverModelID int64) ([]scgo.DriveModel, error) {
-	c.Lock()
-	defer c.Unlock()
+// Cache caches locations and order options lookups.
+// Concurrent lookups of the same key share a single api request,
+// lookups of different keys run in parallel.
+type Cache struct {
+	client *scgo.Client
+	lru    *lru.Cache
+	ttl    time.Duration
+	group  singleflight.Group
+}
 
-	key := fmt.Sprintf("locations/%d/server_models/%d/drive_models", locationID, serverModelID)
+type cacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
 
+func (c *Cache) get(key string) (interface{}, bool) {
 	val, ok := c.lru.Get(key)
-	if ok {
-		return val.([]scgo.DriveModel), nil
+	if !ok {
+		return nil, false
 	}
 
-	driveModels, err := c.client.Locations.DriveModelOptions(locationID, serverModelID).Collect(c.ctx)
-	if err != nil {
-		return nil, err
+	entry := val.(cacheEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.lru.Remove(key)
+		return nil, false
 	}
 
-	c.lru.Add(key, driveModels)
-
-	return driveModels, nil
+	return entry.value, true
 }
 
-func (c *Cache) OperatingSystems(locationID int64, serverModelID int64) ([]scgo.OperatingSystemOption, error) {
-	c.Lock()
-	defer c.Unlock()
-
-	key := fmt.Sprintf("locations/%d/server_models/%d/operating_systems", locationID, serverModelID)
-
-	val, ok := c.lru.Get(key)
-	if ok {
-		return val.([]scgo.OperatingSystemOption), nil
+func (c *Cache) add(key string, value interface{}) {
+	entry := cacheEntry{value: value}
+	if c.ttl > 0 {
+		entry.expiresAt = time.Now().Add(c.ttl)
 	}
 
-	operatingSystems, err := c.client.Locations.OperatingSystemOptions(locationID, serverModelID).Collect(c.ctx)
-	if err != nil {
-		return nil, err
+	c.lru.Add(key, entry)
+}
+
+// fetch returns the cached value by the key or loads it.
+// Errors aren't cached, so the next lookup retries the request.
+func (c *Cache) fetch(ctx context.Context, key string, load func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if value, ok := c.get(key); ok {
+		return value, nil
 	}
 
-	c.lru.Add(key, operatingSystems)
+	resultChan := c.group.DoChan(key, func() (interface{}, error) {
+		if value, ok := c.get(key); ok {
+			return value, nil
+		}
 
-	return operatingSystems, nil
-}
+		// the request is shared by all waiters,
+		// so cancellation of the caller which started it must not fail the others
+		value, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
 
-func (c *Cache) Uplinks(locationID int64, serverModelID int64) ([]scgo.UplinkOption, error) {
-	c.Lock()
-	defer c.Unlock()
+		c.add(key, value)
 
-	key := fmt.Sprintf("locations/%d/server_models/%d/uplinks", locationID, serverModelID)
+		return value, nil
+	})
 
-	val, ok := c.lru.Get(key)
-	if ok {
-		return val.([]scgo.UplinkOption), nil
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-resultChan:
+		return result.Val, result.Err
 	}
+}
 
-	uplinks, err := c.client.Locations.UplinkOptions(locationID, serverModelID).Collect(c.ctx)
+// cachedCollect returns all items of the collection, cached by the key.
+// The collection is built on cache misses only.
+func cachedCollect[T any](ctx context.Context, c *Cache, key string, collection func() scgo.Collection[T]) ([]T, error) {
+	value, err := c.fetch(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return collection().Collect(ctx)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	c.lru.Add(key, uplinks)
+	return value.([]T), nil
+}
 
-	return uplinks, nil
+func (c *Cache) Locations(ctx context.Context) ([]scgo.Location, error) {
+	return cachedCollect(ctx, c, "locations", func() scgo.Collection[scgo.Location] {
+		return c.client.Locations.Collection()
+	})
 }
 
-func (c *Cache) Bandwidth(locationID int64, serverModelID int64, uplinkModelID int64) ([]scgo.BandwidthOption, error) {
-	c.Lock()
-	defer c.Unlock()
+func (c *Cache) ServerModels(ctx context.Context, locationID int64) ([]scgo.ServerModelOption, error) {
+	key := fmt.Sprintf("locations/%d/server_models", locationID)
 
-	key := fmt.Sprintf("locations/%d/server_models/%d/uplinks/%d/bandwidth", locationID, serverModelID, uplinkModelID)
+	return cachedCollect(ctx, c, key, func() scgo.Collection[scgo.ServerModelOption] {
+		return c.client.Locations.ServerModelOptions(locationID)
+	})
+}
 
-	val, ok := c.lru.Get(key)
-	if ok {
-		return val.([]scgo.BandwidthOption), nil
-	}
+// ServerModel returns the server model with details, such as its default drive slots
+func (c *Cache) ServerModel(ctx context.Context, locationID int64, serverModelID int64) (*scgo.ServerModelOptionDetail, error) {
+	key := fmt.Sprintf("locations/%d/server_models/%d", locationID, serverModelID)
 
-	bandwidthList, err := c.client.Locations.BandwidthOptions(locationID, serverModelID, uplinkModelID).Collect(c.ctx)
+	value, err := c.fetch(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return c.client.Locations.GetServerModelOption(ctx, locationID, serverModelID)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	c.lru.Add(key, bandwidthList)
-
-	return bandwidthList, nil
+	return value.(*scgo.ServerModelOptionDetail), nil
 }
 
-func (c *Cache) LocationGroups() ([]scgo.L2LocationGroup, error) {
-	c.Lock()
-	defer c.Unlock()
-
-	val, ok := c.lru.Get("l2/location_group")
-	if ok {
-		return val.([]scgo.L2LocationGroup), nil
-	}
+func (c *Cache) DriveModels(ctx context.Context, locationID int64, serverModelID int64) ([]scgo.DriveModel, error) {
+	key := fmt.Sprintf("locations/%d/server_models/%d/drive_models", locationID, serverModelID)
 
-	locationGroups, err := c.client.L2Segments.LocationGroups().Collect(c.ctx)
-	if err != nil {
-		return nil, err
-	}
+	return cachedCollect(ctx, c, key, func() scgo.Collection[scgo.DriveModel] {
+		return c.client.Locations.DriveModelOptions(locationID, serverModelID)
+	})
+}
 
-	c.lru.Add("l2/location_group", locationGroups)
+func (c *Cache) OperatingSystems(ctx context.Context, locationID int64, serverModelID int64) ([]scgo.OperatingSystemOption, error) {
+	key := fmt.Sprintf("locations/%d/server_models/%d/operating_systems", locationID, serverModelID)
 
-	return locationGroups, nil
+	return cachedCollect(ctx, c, key, func() scgo.Collection[scgo.OperatingSystemOption] {
+		return c.client.Locations.OperatingSystemOptions(locationID, serverModelID)
+	})
 }
 
-func (c *Cache) CloudComputingRegions() ([]scgo.CloudComputingRegion, error) {
-	c.Lock()
-	defer c.Unlock()
+func (c *Cache) Uplinks(ctx context.Context, locationID int64, serverModelID int64) ([]scgo.UplinkOption, error) {
+	key := fmt.Sprintf("locations/%d/server_models/%d/uplinks", locationID, serverModelID)
 
-	val, ok := c.lru.Get("cloud_computing/regions")
-	if ok {
-		return val.([]scgo.CloudComputingRegion), nil
-	}
+	return cachedCollect(ctx, c, key, func() scgo.Collection[scgo.UplinkOption] {
+		return c.client.Locations.UplinkOptions(locationID, serverModelID)
+	})
+}
 
-	cloudRegions, err := c.client.CloudComputingRegions.Collection().Collect(c.ctx)
-	if err != nil {
-		return nil, err
-	}
+func (c *Cache) Bandwidth(ctx context.Context, locationID int64, serverModelID int64, uplinkModelID int64) ([]scgo.BandwidthOption, error) {
+	key := fmt.Sprintf("locations/%d/server_models/%d/uplinks/%d/bandwidth", locationID, serverModelID, uplinkModelID)
 
-	c.lru.Add("cloud_computing/regions", cloudRegions)
+	return cachedCollect(ctx, c, key, func() scgo.Collection[scgo.BandwidthOption] {
+		return c.client.Locations.BandwidthOptions(locationID, serverModelID, uplinkModelID)
+	})
+}
 
-	return cloudRegions, nil
+func (c *Cache) LocationGroups(ctx context.Context) ([]scgo.L2LocationGroup, error) {
+	return cachedCollect(ctx, c, "l2/location_group", func() scgo.Collection[scgo.L2LocationGroup] {
+		return c.client.L2Segments.LocationGroups()
+	})
 }
 
-func (c *Cache) CloudComputingImages(regionID int64) ([]scgo.CloudComputingImage, error) {
-	c.Lock()
-	defer c.Unlock()
+func (c *Cache) CloudComputingRegions(ctx context.Context) ([]scgo.CloudComputingRegion, error) {
+	return cachedCollect(ctx, c, "cloud_computing/regions", func() scgo.Collection[scgo.CloudComputingRegion] {
+		return c.client.CloudComputingRegions.Collection()
+	})
+}
 
+func (c *Cache) CloudComputingImages(ctx context.Context, regionID int64) ([]scgo.CloudComputingImage, error) {
 	key := fmt.Sprintf("cloud_computing/regions/%d/images", regionID)
 
-	val, ok := c.lru.Get(key)
-	if ok {
-		return val.([]scgo.CloudComputingImage), nil
-	}
-
-	cloudImages, err := c.client.CloudComputingRegions.Images(regionID).Collect(c.ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	c.lru.Add(key, cloudImages)
-
-	return cloudImages, nil
+	return cachedCollect(ctx, c, key, func() scgo.Collection[scgo.CloudComputingImage] {
+		return c.client.CloudComputingRegions.Images(regionID)
+	})
 }
 
-func (c *Cache) CloudComputingFlavors(regionID int64) ([]scgo.CloudComputingFlavor, error) {
-	c.Lock()
-	defer c.Unlock()
-
+func (c *Cache) CloudComputingFlavors(ctx context.Context, regionID int64) ([]scgo.CloudComputingFlavor, error) {
 	key := fmt.Sprintf("cloud_computing/regions/%d/flavors", regionID)
 
-	val, ok := c.lru.Get(key)
-	if ok {
-		return val.([]scgo.CloudComputingFlavor), nil
-	}
-
-	cloudFlavors, err := c.client.CloudComputingRegions.Flavors(regionID).Collect(c.ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	c.lru.Add(key, cloudFlavors)
-
-	return cloudFlavors, nil
+	return cachedCollect(ctx, c, key, func() scgo.Collection[scgo.CloudComputingFlavor] {
+		return c.client.CloudComputingRegions.Flavors(regionID)
+	})
 }
 
-func (c *Cache) SBMOperatingSystems(locationID int64, sbmFlavorModelID int64) ([]scgo.OperatingSystemOption, error) {
-	c.Lock()
-	defer c.Unlock()
-
+func (c *Cache) SBMOperatingSystems(ctx context.Context, locationID int64, sbmFlavorModelID int64) ([]scgo.OperatingSystemOption, error) {
 	key := fmt.Sprintf("locations/%d/sbm_flavor_models/%d/operating_systems", locationID, sbmFlavorModelID)
 
-	val, ok := c.lru.Get(key)
-	if ok {
-		return val.([]scgo.OperatingSystemOption), nil
-	}
-
-	operatingSystems, err := c.client.Locations.SBMOperatingSystemOptions(locationID, sbmFlavorModelID).Collect(c.ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	c.lru.Add(key, operatingSystems)
-
-	return operatingSystems, nil
+	return cachedCollect(ctx, c, key, func() scgo.Collection[scgo.OperatingSystemOption] {
+		return c.client.Locations.SBMOperatingSystemOptions(locationID, sbmFlavorModelID)
+	})
 }
 
-func (c *Cache) SBMFlavors(regionID int64) ([]scgo.SBMFlavor, error) {
-	c.Lock()
-	defer c.Unlock()
-
+func (c *Cache) SBMFlavors(ctx context.Context, regionID int64) ([]scgo.SBMFlavor, error) {
 	key := fmt.Sprintf("sbm/regions/%d/flavors", regionID)
 
-	val, ok := c.lru.Get(key)
-	if ok {
-		return val.([]scgo.SBMFlavor), nil
-	}
-
-	sbmFlavors, err := c.client.Locations.SBMFlavorOptions(regionID).Collect(c.ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	c.lru.Add(key, sbmFlavors)
-
-	return sbmFlavors, nil
+	return cachedCollect(ctx, c, key, func() scgo.Collection[scgo.SBMFlavor] {
+		return c.client.Locations.SBMFlavorOptions(regionID)
+	})
 }
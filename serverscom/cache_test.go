@@ -0,0 +1,138 @@
+package serverscom
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheFetch_SingleFlight(t *testing.T) {
+	cache := NewCache(nil, DefaultCacheConfig())
+
+	var calls int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			value, err := cache.fetch(context.Background(), "key", load)
+			if err != nil || value != "value" {
+				t.Errorf("unexpected result: %v, %v", value, err)
+			}
+		}()
+	}
+
+	// let all goroutines join the in-flight request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected a single load, got %d", calls)
+	}
+}
+
+func TestCacheFetch_KeysDontBlockEachOther(t *testing.T) {
+	cache := NewCache(nil, DefaultCacheConfig())
+
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	go cache.fetch(context.Background(), "slow", func(ctx context.Context) (interface{}, error) {
+		<-blocked
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	value, err := cache.fetch(ctx, "fast", func(ctx context.Context) (interface{}, error) {
+		return "fast", nil
+	})
+	if err != nil || value != "fast" {
+		t.Fatalf("expected lookup of another key not to wait, got %v, %v", value, err)
+	}
+}
+
+func TestCacheFetch_TTL(t *testing.T) {
+	cache := NewCache(nil, CacheConfig{TTL: 20 * time.Millisecond, Capacity: 10})
+
+	var calls int32
+	load := func(ctx context.Context) (interface{}, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	}
+
+	cache.fetch(context.Background(), "key", load)
+	cache.fetch(context.Background(), "key", load)
+	if calls != 1 {
+		t.Fatalf("expected cached value to be reused, got %d loads", calls)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	cache.fetch(context.Background(), "key", load)
+	if calls != 2 {
+		t.Fatalf("expected expired value to be reloaded, got %d loads", calls)
+	}
+}
+
+func TestCacheFetch_ErrorsNotCached(t *testing.T) {
+	cache := NewCache(nil, DefaultCacheConfig())
+
+	var calls int32
+	load := func(ctx context.Context) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return nil, errors.New("temporary")
+		}
+		return "value", nil
+	}
+
+	if _, err := cache.fetch(context.Background(), "key", load); err == nil {
+		t.Fatal("expected the first lookup to fail")
+	}
+
+	if value, err := cache.fetch(context.Background(), "key", load); err != nil || value != "value" {
+		t.Fatalf("expected the second lookup to retry, got %v, %v", value, err)
+	}
+}
+
+func TestCacheFetch_CallerCancelled(t *testing.T) {
+	cache := NewCache(nil, DefaultCacheConfig())
+
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "value", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := cache.fetch(ctx, "key", load)
+		errChan <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-errChan; err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// the shared request isn't aborted by the cancelled caller
+	close(release)
+	if value, err := cache.fetch(context.Background(), "key", load); err != nil || value != "value" {
+		t.Fatalf("expected the shared request to complete, got %v, %v", value, err)
+	}
+}
@@ -2,16 +2,15 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomBandwidthOrderOption() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomBandwidthOrderOptionRead,
+		ReadContext: dataSourceServerscomBandwidthOrderOptionRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -46,9 +45,8 @@ func dataSourceServerscomBandwidthOrderOption() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomBandwidthOrderOptionRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomBandwidthOrderOptionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
@@ -57,7 +55,7 @@ func dataSourceServerscomBandwidthOrderOptionRead(d *schema.ResourceData, meta a
 
 	bw, err := client.Locations.GetBandwidthOption(ctx, int64(locationID), int64(serverModelID), int64(uplinkModelID), int64(bandwidthID))
 	if err != nil {
-		return fmt.Errorf("Error retrieving bandwidth order option: %s", err.Error())
+		return diag.Errorf("Error retrieving bandwidth order option: %s", err.Error())
 	}
 
 	d.SetId(strconv.Itoa(int(bw.ID)))
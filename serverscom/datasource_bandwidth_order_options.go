@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomBandwidthOrderOptions() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomBandwidthOrderOptionsRead,
+		ReadContext: dataSourceServerscomBandwidthOrderOptionsRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -66,9 +66,8 @@ func dataSourceServerscomBandwidthOrderOptions() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomBandwidthOrderOptionsRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomBandwidthOrderOptionsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
@@ -86,14 +85,14 @@ func dataSourceServerscomBandwidthOrderOptionsRead(d *schema.ResourceData, meta
 
 		hash, err := hashFilter(filter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		id = fmt.Sprintf("%s-%s", id, hash)
 	}
 
 	bandwidthOptions, err := collection.Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving bandwidth order options: %s", err.Error())
+		return diag.Errorf("Error retrieving bandwidth order options: %s", err.Error())
 	}
 
 	optionList := make([]map[string]any, 0, len(bandwidthOptions))
@@ -112,7 +111,7 @@ func dataSourceServerscomBandwidthOrderOptionsRead(d *schema.ResourceData, meta
 
 	d.SetId(id)
 	if err := d.Set("bandwidth_options", optionList); err != nil {
-		return fmt.Errorf("Error setting bandwidth order options: %s", err.Error())
+		return diag.Errorf("Error setting bandwidth order options: %s", err.Error())
 	}
 
 	return nil
@@ -2,115 +2,121 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomCloudInstance() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomCloudInstanceRead,
+		ReadContext: dataSourceServerscomCloudInstanceRead,
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"openstack_uuid": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"region_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"region_code": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"flavor_id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"flavor_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"image_id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"image_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"public_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"private_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"local_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"public_ipv6_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"ipv6_enabled": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"gpn_enabled": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"backup_copies": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"public_port_blocked": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"labels": {
-				Type:     schema.TypeMap,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"created_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"updated_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		Schema: withLookupFields(cloudInstanceDataSourceSchema(), "name", "label_selector"),
+	}
+}
+
+// cloudInstanceDataSourceSchema returns computed attributes of the cloud computing instance
+func cloudInstanceDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"openstack_uuid": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"region_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"region_code": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"flavor_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"flavor_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"image_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"image_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"public_ipv4_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"private_ipv4_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"local_ipv4_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"public_ipv6_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"ipv6_enabled": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"gpn_enabled": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"backup_copies": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"public_port_blocked": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"labels": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"created_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"updated_at": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourceServerscomCloudInstanceRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomCloudInstanceRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
-	instanceID := d.Get("id").(string)
+	instanceID, err := lookupDataSourceID(ctx, d, meta, "cloud computing instance", "name", cloudComputingInstanceLookup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	instance, err := client.CloudComputingInstances.Get(ctx, instanceID)
 	if err != nil {
-		return fmt.Errorf("Error retrieving cloud instance: %s", err.Error())
+		return diag.Errorf("Error retrieving cloud instance: %s", err.Error())
 	}
 
 	d.SetId(instance.ID)
@@ -132,8 +138,8 @@ func dataSourceServerscomCloudInstanceRead(d *schema.ResourceData, meta any) err
 	d.Set("backup_copies", instance.BackupCopies)
 	d.Set("public_port_blocked", instance.PublicPortBlocked)
 	d.Set("labels", instance.Labels)
-	d.Set("created_at", instance.Created)
-	d.Set("updated_at", instance.Updated)
+	d.Set("created_at", formatTime(instance.Created))
+	d.Set("updated_at", formatTime(instance.Updated))
 
 	return nil
 }
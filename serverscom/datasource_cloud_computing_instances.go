@@ -0,0 +1,120 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func dataSourceServerscomCloudInstances() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerscomCloudInstancesRead,
+
+		Schema: map[string]*schema.Schema{
+			"filter": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"region_id":      {Type: schema.TypeInt, Optional: true},
+						"label_selector": {Type: schema.TypeString, Optional: true},
+						"search_pattern": {Type: schema.TypeString, Optional: true},
+						"status":         {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+
+			"cloud_computing_instances": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: cloudInstanceDataSourceSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServerscomCloudInstancesRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
+
+	col := client.CloudComputingInstances.Collection()
+
+	id := "cloud-computing-instances"
+	status := ""
+
+	if v, ok := d.GetOk("filter"); ok && v.([]any)[0] != nil {
+		filter := v.([]any)[0].(map[string]any)
+
+		if id, ok := filter["region_id"]; ok && id.(int) > 0 {
+			col = col.SetParam("region_id", strconv.Itoa(id.(int)))
+		}
+		if ls, ok := filter["label_selector"]; ok && ls.(string) != "" {
+			col = col.SetParam("label_selector", ls.(string))
+		}
+		if sp, ok := filter["search_pattern"]; ok && sp.(string) != "" {
+			col = col.SetParam("search_pattern", sp.(string))
+		}
+		// the cloud instances api doesn't filter by status, it's applied to the collected instances
+		status = filter["status"].(string)
+
+		hash, err := hashFilter(filter)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		id = fmt.Sprintf("cloud-computing-instances-%s", hash)
+	}
+
+	instances, err := col.Collect(ctx)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Error retrieving cloud instances: %s", err))
+	}
+
+	list := make([]map[string]any, 0, len(instances))
+	for _, instance := range instances {
+		if status != "" && instance.Status != status {
+			continue
+		}
+
+		list = append(list, flattenCloudInstance(instance))
+	}
+
+	d.SetId(id)
+	if err := d.Set("cloud_computing_instances", list); err != nil {
+		return diag.FromErr(fmt.Errorf("Error setting cloud instances: %s", err))
+	}
+
+	return nil
+}
+
+// flattenCloudInstance converts the cloud instance to the attributes of the cloud instance data source
+func flattenCloudInstance(instance scgo.CloudComputingInstance) map[string]any {
+	return map[string]any{
+		"id":                   instance.ID,
+		"openstack_uuid":       instance.OpenstackUUID,
+		"name":                 instance.Name,
+		"status":               instance.Status,
+		"region_id":            int(instance.RegionID),
+		"region_code":          instance.RegionCode,
+		"flavor_id":            instance.FlavorID,
+		"flavor_name":          instance.FlavorName,
+		"image_id":             instance.ImageID,
+		"image_name":           stringValue(instance.ImageName),
+		"public_ipv4_address":  stringValue(instance.PublicIPv4Address),
+		"private_ipv4_address": stringValue(instance.PrivateIPv4Address),
+		"local_ipv4_address":   stringValue(instance.LocalIPv4Address),
+		"public_ipv6_address":  stringValue(instance.PublicIPv6Address),
+		"ipv6_enabled":         instance.IPv6Enabled,
+		"gpn_enabled":          instance.GPNEnabled,
+		"backup_copies":        instance.BackupCopies,
+		"public_port_blocked":  instance.PublicPortBlocked,
+		"labels":               instance.Labels,
+		"created_at":           formatTime(instance.Created),
+		"updated_at":           formatTime(instance.Updated),
+	}
+}
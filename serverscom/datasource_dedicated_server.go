@@ -2,148 +2,154 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomDedicatedServer() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomDedicatedServerRead,
+		ReadContext: dataSourceServerscomDedicatedServerRead,
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"rack_id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"title": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"location_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"location_code": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"operational_status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"power_status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"configuration": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"private_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"public_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"lease_start_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"scheduled_release_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"oob_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			// Flattened configuration_details fields
-			"ram_size": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"server_model_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"server_model_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"bandwidth_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"bandwidth_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"private_uplink_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"private_uplink_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"public_uplink_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"public_uplink_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"operating_system_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"operating_system_full_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"labels": {
-				Type:     schema.TypeMap,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"created_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"updated_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		Schema: withLookupFields(dedicatedServerDataSourceSchema(), "title", "label_selector"),
+	}
+}
+
+// dedicatedServerDataSourceSchema returns computed attributes of the dedicated server
+func dedicatedServerDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"rack_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"title": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"location_code": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"operational_status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"power_status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"configuration": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"private_ipv4_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"public_ipv4_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"lease_start_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"scheduled_release_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"oob_ipv4_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		// Flattened configuration_details fields
+		"ram_size": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"server_model_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"server_model_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"bandwidth_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"bandwidth_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"private_uplink_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"private_uplink_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"public_uplink_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"public_uplink_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"operating_system_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"operating_system_full_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"labels": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"created_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"updated_at": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourceServerscomDedicatedServerRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomDedicatedServerRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
-	serverID := d.Get("id").(string)
+	serverID, err := lookupDataSourceID(ctx, d, meta, "dedicated server", "title", hostLookup("dedicated_server"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	server, err := client.Hosts.GetDedicatedServer(ctx, serverID)
 	if err != nil {
-		return fmt.Errorf("Error retrieving dedicated server: %s", err.Error())
+		return diag.Errorf("Error retrieving dedicated server: %s", err.Error())
 	}
 
 	d.SetId(server.ID)
@@ -158,7 +164,7 @@ func dataSourceServerscomDedicatedServerRead(d *schema.ResourceData, meta any) e
 	d.Set("private_ipv4_address", server.PrivateIPv4Address)
 	d.Set("public_ipv4_address", server.PublicIPv4Address)
 	d.Set("lease_start_at", server.LeaseStart)
-	d.Set("scheduled_release_at", server.ScheduledRelease)
+	d.Set("scheduled_release_at", formatScheduledRelease(server.ScheduledRelease))
 	d.Set("type", server.Type)
 	d.Set("oob_ipv4_address", server.OobIPv4Address)
 
@@ -176,8 +182,8 @@ func dataSourceServerscomDedicatedServerRead(d *schema.ResourceData, meta any) e
 	d.Set("operating_system_full_name", server.ConfigurationDetails.OperatingSystemFullName)
 
 	d.Set("labels", server.Labels)
-	d.Set("created_at", server.Created)
-	d.Set("updated_at", server.Updated)
+	d.Set("created_at", formatTime(server.Created))
+	d.Set("updated_at", formatTime(server.Updated))
 
 	return nil
 }
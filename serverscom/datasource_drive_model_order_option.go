@@ -2,16 +2,15 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomDriveModelOrderOption() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomDriveModelOrderOptionRead,
+		ReadContext: dataSourceServerscomDriveModelOrderOptionRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -50,9 +49,8 @@ func dataSourceServerscomDriveModelOrderOption() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomDriveModelOrderOptionRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomDriveModelOrderOptionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
@@ -60,7 +58,7 @@ func dataSourceServerscomDriveModelOrderOptionRead(d *schema.ResourceData, meta
 
 	model, err := client.Locations.GetDriveModelOption(ctx, int64(locationID), int64(serverModelID), int64(driveModelID))
 	if err != nil {
-		return fmt.Errorf("Error retrieving drive model order option: %s", err.Error())
+		return diag.Errorf("Error retrieving drive model order option: %s", err.Error())
 	}
 
 	d.SetId(strconv.Itoa(int(model.ID)))
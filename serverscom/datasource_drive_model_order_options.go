@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomDriveModelOrderOptions() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomDriveModelOrderOptionsRead,
+		ReadContext: dataSourceServerscomDriveModelOrderOptionsRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -80,9 +80,8 @@ func dataSourceServerscomDriveModelOrderOptions() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomDriveModelOrderOptionsRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomDriveModelOrderOptionsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
@@ -106,14 +105,14 @@ func dataSourceServerscomDriveModelOrderOptionsRead(d *schema.ResourceData, meta
 
 		hash, err := hashFilter(filter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		id = fmt.Sprintf("%s-%s", id, hash)
 	}
 
 	driveModels, err := collection.Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving drive model order options: %s", err.Error())
+		return diag.Errorf("Error retrieving drive model order options: %s", err.Error())
 	}
 
 	driveModelList := make([]map[string]any, 0, len(driveModels))
@@ -130,7 +129,7 @@ func dataSourceServerscomDriveModelOrderOptionsRead(d *schema.ResourceData, meta
 
 	d.SetId(id)
 	if err := d.Set("drive_models", driveModelList); err != nil {
-		return fmt.Errorf("Error setting drive model order options: %s", err.Error())
+		return diag.Errorf("Error setting drive model order options: %s", err.Error())
 	}
 
 	return nil
@@ -0,0 +1,148 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func dataSourceServerscomDedicatedServers() *schema.Resource {
+	return dataSourceServerscomHosts("dedicated_server", "dedicated_servers", dedicatedServerDataSourceSchema())
+}
+
+func dataSourceServerscomSBMServers() *schema.Resource {
+	return dataSourceServerscomHosts("sbm_server", "sbm_servers", sbmServerDataSourceSchema())
+}
+
+// dataSourceServerscomHosts lists hosts of the type with the attributes of the singular data source
+func dataSourceServerscomHosts(hostType, attribute string, elemSchema map[string]*schema.Schema) *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerscomHostsRead(hostType, attribute),
+
+		Schema: map[string]*schema.Schema{
+			"filter": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"location_id":    {Type: schema.TypeInt, Optional: true},
+						"rack_id":        {Type: schema.TypeString, Optional: true},
+						"label_selector": {Type: schema.TypeString, Optional: true},
+						"search_pattern": {Type: schema.TypeString, Optional: true},
+						"status":         {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+
+			attribute: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: elemSchema,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServerscomHostsRead(hostType, attribute string) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+		client := meta.(*ProviderMeta).Client
+
+		col := client.Hosts.Collection().SetParam("type", hostType)
+
+		// ids are prefixed like the ids of other list data sources, e.g. dedicated-servers
+		idPrefix := strings.ReplaceAll(attribute, "_", "-")
+		id := idPrefix
+		status := ""
+
+		if v, ok := d.GetOk("filter"); ok && v.([]any)[0] != nil {
+			filter := v.([]any)[0].(map[string]any)
+
+			if id, ok := filter["location_id"]; ok && id.(int) > 0 {
+				col = col.SetParam("location_id", strconv.Itoa(id.(int)))
+			}
+			if rackID, ok := filter["rack_id"]; ok && rackID.(string) != "" {
+				col = col.SetParam("rack_id", rackID.(string))
+			}
+			if ls, ok := filter["label_selector"]; ok && ls.(string) != "" {
+				col = col.SetParam("label_selector", ls.(string))
+			}
+			if sp, ok := filter["search_pattern"]; ok && sp.(string) != "" {
+				col = col.SetParam("search_pattern", sp.(string))
+			}
+			// the hosts api doesn't filter by status, it's applied to the collected hosts
+			status = filter["status"].(string)
+
+			hash, err := hashFilter(filter)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			id = fmt.Sprintf("%s-%s", idPrefix, hash)
+		}
+
+		hosts, err := col.Collect(ctx)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("Error retrieving %s: %s", attribute, err))
+		}
+
+		list := make([]map[string]any, 0, len(hosts))
+		for _, host := range hosts {
+			if host.Type != hostType || (status != "" && host.Status != status) {
+				continue
+			}
+
+			list = append(list, flattenHost(host))
+		}
+
+		d.SetId(id)
+		if err := d.Set(attribute, list); err != nil {
+			return diag.FromErr(fmt.Errorf("Error setting %s: %s", attribute, err))
+		}
+
+		return nil
+	}
+}
+
+// flattenHost converts the host to the attributes of the dedicated server and SBM server data sources
+func flattenHost(host scgo.Host) map[string]any {
+	return map[string]any{
+		"id":                   host.ID,
+		"rack_id":              host.RackID,
+		"title":                host.Title,
+		"location_id":          int(host.LocationID),
+		"location_code":        host.LocationCode,
+		"status":               host.Status,
+		"operational_status":   host.OperationalStatus,
+		"power_status":         host.PowerStatus,
+		"configuration":        host.Configuration,
+		"private_ipv4_address": stringValue(host.PrivateIPv4Address),
+		"public_ipv4_address":  stringValue(host.PublicIPv4Address),
+		"lease_start_at":       stringValue(host.LeaseStart),
+		"scheduled_release_at": formatScheduledRelease(host.ScheduledRelease),
+		"type":                 host.Type,
+		"oob_ipv4_address":     stringValue(host.OobIPv4Address),
+
+		"ram_size":                   host.ConfigurationDetails.RAMSize,
+		"server_model_id":            intValue(host.ConfigurationDetails.ServerModelID),
+		"server_model_name":          stringValue(host.ConfigurationDetails.ServerModelName),
+		"bandwidth_id":               intValue(host.ConfigurationDetails.BandwidthID),
+		"bandwidth_name":             stringValue(host.ConfigurationDetails.BandwidthName),
+		"private_uplink_id":          intValue(host.ConfigurationDetails.PrivateUplinkID),
+		"private_uplink_name":        stringValue(host.ConfigurationDetails.PrivateUplinkName),
+		"public_uplink_id":           intValue(host.ConfigurationDetails.PublicUplinkID),
+		"public_uplink_name":         stringValue(host.ConfigurationDetails.PublicUplinkName),
+		"operating_system_id":        intValue(host.ConfigurationDetails.OperatingSystemID),
+		"operating_system_full_name": stringValue(host.ConfigurationDetails.OperatingSystemFullName),
+
+		"labels":     host.Labels,
+		"created_at": formatTime(host.Created),
+		"updated_at": formatTime(host.Updated),
+	}
+}
@@ -0,0 +1,57 @@
+package serverscom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func TestFlattenHost(t *testing.T) {
+	address := "192.0.2.10"
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	host := scgo.Host{ID: "BM7zQnVb", Title: "web-01", Type: "dedicated_server", PublicIPv4Address: &address, Created: created}
+
+	flattened := flattenHost(host)
+
+	checkFlattenedAttributes(t, flattened, dataSourceServerscomDedicatedServers().Schema["dedicated_servers"])
+
+	if flattened["private_ipv4_address"] != "" || flattened["public_ipv4_address"] != address {
+		t.Errorf("unexpected addresses: %q, %q", flattened["private_ipv4_address"], flattened["public_ipv4_address"])
+	}
+	if flattened["created_at"] != "2024-05-01T10:30:00Z" {
+		t.Errorf("expected created_at in RFC3339, got %q", flattened["created_at"])
+	}
+	if flattened["scheduled_release_at"] != "" {
+		t.Errorf("expected scheduled_release_at to be empty, got %q", flattened["scheduled_release_at"])
+	}
+}
+
+func TestFlattenCloudInstance(t *testing.T) {
+	flattened := flattenCloudInstance(scgo.CloudComputingInstance{ID: "xkazYeJ0", Name: "web-01", RegionID: 1})
+
+	checkFlattenedAttributes(t, flattened, dataSourceServerscomCloudInstances().Schema["cloud_computing_instances"])
+}
+
+// checkFlattenedAttributes checks the flattened object has all attributes of the computed list and nothing else
+func checkFlattenedAttributes(t *testing.T, flattened map[string]any, list *schema.Schema) {
+	t.Helper()
+
+	elemSchema := list.Elem.(*schema.Resource).Schema
+
+	for key, s := range elemSchema {
+		if !s.Computed || s.Required || s.Optional {
+			t.Errorf("expected %s to be computed only", key)
+		}
+		if _, ok := flattened[key]; !ok {
+			t.Errorf("expected %s to be flattened", key)
+		}
+	}
+
+	for key := range flattened {
+		if _, ok := elemSchema[key]; !ok {
+			t.Errorf("unexpected flattened attribute %s", key)
+		}
+	}
+}
@@ -2,67 +2,73 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomL2Segment() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomL2SegmentRead,
+		ReadContext: dataSourceServerscomL2SegmentRead,
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"location_group_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"location_group_code": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"labels": {
-				Type:     schema.TypeMap,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"created_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"updated_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		Schema: withLookupFields(l2SegmentDataSourceSchema(), "name", "label_selector"),
+	}
+}
+
+// l2SegmentDataSourceSchema returns computed attributes of the L2 segment
+func l2SegmentDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location_group_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"location_group_code": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"labels": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"created_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"updated_at": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourceServerscomL2SegmentRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomL2SegmentRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
-	L2SegmentID := d.Get("id").(string)
+	L2SegmentID, err := lookupDataSourceID(ctx, d, meta, "L2 segment", "name", l2SegmentLookup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	L2Segment, err := client.L2Segments.Get(ctx, L2SegmentID)
 	if err != nil {
-		return fmt.Errorf("Error retrieving L2 segment: %s", err.Error())
+		return diag.Errorf("Error retrieving L2 segment: %s", err.Error())
 	}
 
 	d.SetId(L2Segment.ID)
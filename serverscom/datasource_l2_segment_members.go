@@ -2,16 +2,15 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomL2SegmentMembers() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomL2SegmentMembersRead,
+		ReadContext: dataSourceServerscomL2SegmentMembersRead,
 
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -64,15 +63,14 @@ func dataSourceServerscomL2SegmentMembers() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomL2SegmentMembersRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomL2SegmentMembersRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	L2SegmentID := d.Get("id").(string)
 
 	members, err := client.L2Segments.Members(L2SegmentID).Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving L2 members: %s", err.Error())
+		return diag.Errorf("Error retrieving L2 members: %s", err.Error())
 	}
 
 	d.SetId(L2SegmentID)
@@ -93,7 +91,7 @@ func dataSourceServerscomL2SegmentMembersRead(d *schema.ResourceData, meta any)
 	}
 
 	if err := d.Set("members", membersList); err != nil {
-		return fmt.Errorf("Error setting members: %s", err.Error())
+		return diag.Errorf("Error setting members: %s", err.Error())
 	}
 
 	return nil
@@ -4,62 +4,84 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomLocation() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomLocationRead,
-
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:     schema.TypeInt,
-				Required: true,
-			},
-			"name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"code": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"supported_features": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"l2_segments_enabled": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"private_racks_enabled": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"load_balancers_enabled": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
+		ReadContext: dataSourceServerscomLocationRead,
+
+		Schema: withLookupFields(locationDataSourceSchema(), "code", ""),
+	}
+}
+
+// locationDataSourceSchema returns computed attributes of the location
+func locationDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"code": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"supported_features": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"l2_segments_enabled": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"private_racks_enabled": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"load_balancers_enabled": {
+			Type:     schema.TypeBool,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourceServerscomLocationRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomLocationRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
-	locationID := d.Get("id").(int)
+	var location *scgo.Location
 
-	location, err := client.Locations.GetLocation(ctx, int64(locationID))
-	if err != nil {
-		return fmt.Errorf("Error retrieving location: %s", err.Error())
+	if locationID, ok := d.GetOk("id"); ok {
+		found, err := client.Locations.GetLocation(ctx, int64(locationID.(int)))
+		if err != nil {
+			return diag.Errorf("Error retrieving location: %s", err.Error())
+		}
+
+		location = found
+	} else {
+		locations, err := meta.(*ProviderMeta).Cache.Locations(ctx)
+		if err != nil {
+			return diag.Errorf("Error retrieving locations: %s", err.Error())
+		}
+
+		found, err := findLocationByCode(locations, d.Get("code").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		location = found
 	}
 
 	d.SetId(strconv.Itoa(int(location.ID)))
@@ -73,3 +95,23 @@ func dataSourceServerscomLocationRead(d *schema.ResourceData, meta any) error {
 
 	return nil
 }
+
+// findLocationByCode returns the location with the code, codes are compared case insensitively
+func findLocationByCode(locations []scgo.Location, code string) (*scgo.Location, error) {
+	var results []scgo.Location
+
+	for _, location := range locations {
+		if strings.EqualFold(location.Code, code) {
+			results = append(results, location)
+		}
+	}
+
+	switch len(results) {
+	case 0:
+		return nil, fmt.Errorf("no location found with code %s", code)
+	case 1:
+		return &results[0], nil
+	}
+
+	return nil, fmt.Errorf("too many locations found with code %s (found %d, expected 1)", code, len(results))
+}
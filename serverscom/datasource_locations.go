@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomLocations() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomLocationsRead,
+		ReadContext: dataSourceServerscomLocationsRead,
 
 		Schema: map[string]*schema.Schema{
 			"filter": {
@@ -71,9 +71,8 @@ func dataSourceServerscomLocations() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomLocationsRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomLocationsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	collection := client.Locations.Collection()
 
@@ -86,14 +85,14 @@ func dataSourceServerscomLocationsRead(d *schema.ResourceData, meta any) error {
 		}
 		hash, err := hashFilter(filter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		id = fmt.Sprintf("locations-%s", hash)
 	}
 
 	locations, err := collection.Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving locations: %s", err.Error())
+		return diag.Errorf("Error retrieving locations: %s", err.Error())
 	}
 
 	locationList := make([]map[string]any, 0, len(locations))
@@ -113,7 +112,7 @@ func dataSourceServerscomLocationsRead(d *schema.ResourceData, meta any) error {
 
 	d.SetId(id)
 	if err := d.Set("locations", locationList); err != nil {
-		return fmt.Errorf("Error setting locations: %s", err.Error())
+		return diag.Errorf("Error setting locations: %s", err.Error())
 	}
 
 	return nil
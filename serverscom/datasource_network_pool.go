@@ -32,7 +32,7 @@ func datasourceServerscomNetworkPool() *schema.Resource {
 }
 
 func datasourceServerscomNetworkPoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
 
 	var foundNetworkPool *scgo.NetworkPool
 
@@ -2,16 +2,15 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomOperatingSystemOrderOption() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomOperatingSystemOrderOptionRead,
+		ReadContext: dataSourceServerscomOperatingSystemOrderOptionRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -51,9 +50,8 @@ func dataSourceServerscomOperatingSystemOrderOption() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomOperatingSystemOrderOptionRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomOperatingSystemOrderOptionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
@@ -61,7 +59,7 @@ func dataSourceServerscomOperatingSystemOrderOptionRead(d *schema.ResourceData,
 
 	os, err := client.Locations.GetOperatingSystemOption(ctx, int64(locationID), int64(serverModelID), int64(operatingSystemID))
 	if err != nil {
-		return fmt.Errorf("Error retrieving operating system order option: %s", err.Error())
+		return diag.Errorf("Error retrieving operating system order option: %s", err.Error())
 	}
 
 	d.SetId(strconv.Itoa(int(os.ID)))
@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomOperatingSystemOrderOptions() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomOperatingSystemOrderOptionsRead,
+		ReadContext: dataSourceServerscomOperatingSystemOrderOptionsRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -58,9 +58,8 @@ func dataSourceServerscomOperatingSystemOrderOptions() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomOperatingSystemOrderOptionsRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomOperatingSystemOrderOptionsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
@@ -69,7 +68,7 @@ func dataSourceServerscomOperatingSystemOrderOptionsRead(d *schema.ResourceData,
 
 	operatingSystems, err := collection.Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving operating system order options: %s", err.Error())
+		return diag.Errorf("Error retrieving operating system order options: %s", err.Error())
 	}
 
 	osList := make([]map[string]any, 0, len(operatingSystems))
@@ -86,7 +85,7 @@ func dataSourceServerscomOperatingSystemOrderOptionsRead(d *schema.ResourceData,
 
 	d.SetId(fmt.Sprintf("operating_systems-%d-%d", locationID, serverModelID))
 	if err := d.Set("operating_systems", osList); err != nil {
-		return fmt.Errorf("Error setting operating system order options: %s", err.Error())
+		return diag.Errorf("Error setting operating system order options: %s", err.Error())
 	}
 
 	return nil
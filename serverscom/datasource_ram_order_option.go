@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomRamOrderOption() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomRamOrderOptionRead,
+		ReadContext: dataSourceServerscomRamOrderOptionRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -35,9 +35,8 @@ func dataSourceServerscomRamOrderOption() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomRamOrderOptionRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomRamOrderOptionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
@@ -48,7 +47,7 @@ func dataSourceServerscomRamOrderOptionRead(d *schema.ResourceData, meta any) er
 
 	options, err := client.Locations.RAMOptions(int64(locationID), int64(serverModelID)).Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving RAM order options: %s", err.Error())
+		return diag.Errorf("Error retrieving RAM order options: %s", err.Error())
 	}
 
 	for _, ram := range options {
@@ -61,5 +60,5 @@ func dataSourceServerscomRamOrderOptionRead(d *schema.ResourceData, meta any) er
 		}
 	}
 
-	return fmt.Errorf("No RAM option found matching ram=%d and type=%s", targetRAM, targetType)
+	return diag.Errorf("No RAM option found matching ram=%d and type=%s", targetRAM, targetType)
 }
@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomRamOrderOptions() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomRamOrderOptionsRead,
+		ReadContext: dataSourceServerscomRamOrderOptionsRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -41,16 +41,15 @@ func dataSourceServerscomRamOrderOptions() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomRamOrderOptionsRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomRamOrderOptionsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
 
 	options, err := client.Locations.RAMOptions(int64(locationID), int64(serverModelID)).Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving RAM order options: %s", err.Error())
+		return diag.Errorf("Error retrieving RAM order options: %s", err.Error())
 	}
 
 	optionList := make([]map[string]any, 0, len(options))
@@ -65,7 +64,7 @@ func dataSourceServerscomRamOrderOptionsRead(d *schema.ResourceData, meta any) e
 	d.SetId(id)
 
 	if err := d.Set("ram_options", optionList); err != nil {
-		return fmt.Errorf("Error setting RAM order options: %s", err.Error())
+		return diag.Errorf("Error setting RAM order options: %s", err.Error())
 	}
 
 	return nil
@@ -7,7 +7,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomRBSFlavor() *schema.Resource {
@@ -25,7 +24,7 @@ func dataSourceServerscomRBSFlavor() *schema.Resource {
 }
 
 func dataSourceServerscomRBSFlavorRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
 
 	locationID := int64(d.Get("location_id").(int))
 	flavorID := int64(d.Get("flavor_id").(int))
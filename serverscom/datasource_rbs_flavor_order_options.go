@@ -6,7 +6,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomRBSFlavors() *schema.Resource {
@@ -34,7 +33,7 @@ func dataSourceServerscomRBSFlavors() *schema.Resource {
 }
 
 func dataSourceServerscomRBSFlavorsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 
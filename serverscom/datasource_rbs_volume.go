@@ -6,41 +6,48 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomRBSVolume() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceServerscomRBSVolumeRead,
 
-		Schema: map[string]*schema.Schema{
-			"id": {Type: schema.TypeString, Required: true},
+		Schema: withLookupFields(rbsVolumeDataSourceSchema(), "name", "label_selector"),
+	}
+}
+
+// rbsVolumeDataSourceSchema returns computed attributes of the RBS volume
+func rbsVolumeDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {Type: schema.TypeString, Computed: true},
 
-			"name":   {Type: schema.TypeString, Computed: true},
-			"size":   {Type: schema.TypeInt, Computed: true},
-			"status": {Type: schema.TypeString, Computed: true},
-			"labels": {
-				Type: schema.TypeMap,
-				Elem: &schema.Schema{Type: schema.TypeString}, Computed: true,
-			},
-			"location_id":   {Type: schema.TypeInt, Computed: true},
-			"location_code": {Type: schema.TypeString, Computed: true},
-			"ip_address":    {Type: schema.TypeString, Computed: true},
-			"flavor_id":     {Type: schema.TypeInt, Computed: true},
-			"flavor_name":   {Type: schema.TypeString, Computed: true},
-			"iops":          {Type: schema.TypeFloat, Computed: true},
-			"bandwidth":     {Type: schema.TypeFloat, Computed: true},
-			"target_iqn":    {Type: schema.TypeString, Computed: true},
-			"created_at":    {Type: schema.TypeString, Computed: true},
-			"updated_at":    {Type: schema.TypeString, Computed: true},
+		"name":   {Type: schema.TypeString, Computed: true},
+		"size":   {Type: schema.TypeInt, Computed: true},
+		"status": {Type: schema.TypeString, Computed: true},
+		"labels": {
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{Type: schema.TypeString}, Computed: true,
 		},
+		"location_id":   {Type: schema.TypeInt, Computed: true},
+		"location_code": {Type: schema.TypeString, Computed: true},
+		"ip_address":    {Type: schema.TypeString, Computed: true},
+		"flavor_id":     {Type: schema.TypeInt, Computed: true},
+		"flavor_name":   {Type: schema.TypeString, Computed: true},
+		"iops":          {Type: schema.TypeFloat, Computed: true},
+		"bandwidth":     {Type: schema.TypeFloat, Computed: true},
+		"target_iqn":    {Type: schema.TypeString, Computed: true},
+		"created_at":    {Type: schema.TypeString, Computed: true},
+		"updated_at":    {Type: schema.TypeString, Computed: true},
 	}
 }
 
 func dataSourceServerscomRBSVolumeRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
 
-	id := d.Get("id").(string)
+	id, err := lookupDataSourceID(ctx, d, meta, "RBS volume", "name", rbsVolumeLookup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	vol, err := client.RemoteBlockStorageVolumes.Get(ctx, id)
 	if err != nil {
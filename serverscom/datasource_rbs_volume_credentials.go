@@ -6,7 +6,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomRBSVolumeCredentials() *schema.Resource {
@@ -24,7 +23,7 @@ func dataSourceServerscomRBSVolumeCredentials() *schema.Resource {
 }
 
 func dataSourceServerscomRBSVolumeCredentialsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
 	volumeID := d.Get("volume_id").(string)
 
 	creds, err := client.RemoteBlockStorageVolumes.GetCredentials(ctx, volumeID)
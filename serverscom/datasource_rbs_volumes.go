@@ -7,7 +7,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomRBSVolumes() *schema.Resource {
@@ -58,7 +57,7 @@ func dataSourceServerscomRBSVolumes() *schema.Resource {
 }
 
 func dataSourceServerscomRBSVolumesRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
 
 	col := client.RemoteBlockStorageVolumes.Collection()
 
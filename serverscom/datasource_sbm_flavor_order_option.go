@@ -2,16 +2,15 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomSbmFlavorOrderOption() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomSbmFlavorOrderOptionRead,
+		ReadContext: dataSourceServerscomSbmFlavorOrderOptionRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -78,16 +77,15 @@ func dataSourceServerscomSbmFlavorOrderOption() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomSbmFlavorOrderOptionRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomSbmFlavorOrderOptionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	sbmFlavorID := d.Get("id").(int)
 
 	flavor, err := client.Locations.GetSBMFlavorOption(ctx, int64(locationID), int64(sbmFlavorID))
 	if err != nil {
-		return fmt.Errorf("Error retrieving SBM flavor order option: %s", err.Error())
+		return diag.Errorf("Error retrieving SBM flavor order option: %s", err.Error())
 	}
 
 	d.SetId(strconv.Itoa(int(flavor.ID)))
@@ -5,13 +5,13 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomSbmFlavorOrderOptions() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomSbmFlavorOrderOptionsRead,
+		ReadContext: dataSourceServerscomSbmFlavorOrderOptionsRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -104,9 +104,8 @@ func dataSourceServerscomSbmFlavorOrderOptions() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomSbmFlavorOrderOptionsRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomSbmFlavorOrderOptionsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 
@@ -126,14 +125,14 @@ func dataSourceServerscomSbmFlavorOrderOptionsRead(d *schema.ResourceData, meta
 
 		hash, err := hashFilter(filter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		id = fmt.Sprintf("%s-%s", id, hash)
 	}
 
 	options, err := collection.Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving SBM flavor order options: %s", err.Error())
+		return diag.Errorf("Error retrieving SBM flavor order options: %s", err.Error())
 	}
 
 	optionList := make([]map[string]any, 0, len(options))
@@ -159,7 +158,7 @@ func dataSourceServerscomSbmFlavorOrderOptionsRead(d *schema.ResourceData, meta
 	d.SetId(id)
 
 	if err := d.Set("sbm_flavors", optionList); err != nil {
-		return fmt.Errorf("Error setting SBM flavor order options: %s", err.Error())
+		return diag.Errorf("Error setting SBM flavor order options: %s", err.Error())
 	}
 
 	return nil
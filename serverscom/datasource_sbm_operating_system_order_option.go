@@ -2,16 +2,15 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomSbmOperatingSystemOrderOption() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomSbmOperatingSystemOrderOptionRead,
+		ReadContext: dataSourceServerscomSbmOperatingSystemOrderOptionRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -51,9 +50,8 @@ func dataSourceServerscomSbmOperatingSystemOrderOption() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomSbmOperatingSystemOrderOptionRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomSbmOperatingSystemOrderOptionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	sbmFlavorModelID := d.Get("sbm_flavor_model_id").(int)
@@ -61,7 +59,7 @@ func dataSourceServerscomSbmOperatingSystemOrderOptionRead(d *schema.ResourceDat
 
 	os, err := client.Locations.GetSBMOperatingSystemOption(ctx, int64(locationID), int64(sbmFlavorModelID), int64(osID))
 	if err != nil {
-		return fmt.Errorf("Error retrieving SBM operating system order option: %s", err.Error())
+		return diag.Errorf("Error retrieving SBM operating system order option: %s", err.Error())
 	}
 
 	d.SetId(strconv.Itoa(int(os.ID)))
@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomSbmOperatingSystemOrderOptions() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomSbmOperatingSystemOrderOptionsRead,
+		ReadContext: dataSourceServerscomSbmOperatingSystemOrderOptionsRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -58,9 +58,8 @@ func dataSourceServerscomSbmOperatingSystemOrderOptions() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomSbmOperatingSystemOrderOptionsRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomSbmOperatingSystemOrderOptionsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	sbmFlavorModelID := d.Get("sbm_flavor_model_id").(int)
@@ -69,7 +68,7 @@ func dataSourceServerscomSbmOperatingSystemOrderOptionsRead(d *schema.ResourceDa
 
 	options, err := collection.Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving SBM operating system order options: %s", err.Error())
+		return diag.Errorf("Error retrieving SBM operating system order options: %s", err.Error())
 	}
 
 	optionList := make([]map[string]any, 0, len(options))
@@ -88,7 +87,7 @@ func dataSourceServerscomSbmOperatingSystemOrderOptionsRead(d *schema.ResourceDa
 	d.SetId(id)
 
 	if err := d.Set("sbm_operating_systems", optionList); err != nil {
-		return fmt.Errorf("Error setting SBM operating system order options: %s", err.Error())
+		return diag.Errorf("Error setting SBM operating system order options: %s", err.Error())
 	}
 
 	return nil
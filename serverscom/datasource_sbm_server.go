@@ -2,148 +2,154 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomSBMServer() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomSBMServerRead,
+		ReadContext: dataSourceServerscomSBMServerRead,
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"rack_id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"title": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"location_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"location_code": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"operational_status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"power_status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"configuration": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"private_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"public_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"lease_start_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"scheduled_release_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"oob_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			// Flattened configuration_details fields
-			"ram_size": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"server_model_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"server_model_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"bandwidth_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"bandwidth_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"private_uplink_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"private_uplink_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"public_uplink_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"public_uplink_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"operating_system_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"operating_system_full_name": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"labels": {
-				Type:     schema.TypeMap,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"created_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"updated_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		Schema: withLookupFields(sbmServerDataSourceSchema(), "title", "label_selector"),
+	}
+}
+
+// sbmServerDataSourceSchema returns computed attributes of the SBM server
+func sbmServerDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"rack_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"title": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"location_code": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"operational_status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"power_status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"configuration": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"private_ipv4_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"public_ipv4_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"lease_start_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"scheduled_release_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"oob_ipv4_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		// Flattened configuration_details fields
+		"ram_size": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"server_model_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"server_model_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"bandwidth_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"bandwidth_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"private_uplink_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"private_uplink_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"public_uplink_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"public_uplink_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"operating_system_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"operating_system_full_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"labels": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"created_at": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"updated_at": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourceServerscomSBMServerRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomSBMServerRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
-	serverID := d.Get("id").(string)
+	serverID, err := lookupDataSourceID(ctx, d, meta, "SBM server", "title", hostLookup("sbm_server"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	server, err := client.Hosts.GetSBMServer(ctx, serverID)
 	if err != nil {
-		return fmt.Errorf("Error retrieving sbm server: %s", err.Error())
+		return diag.Errorf("Error retrieving sbm server: %s", err.Error())
 	}
 
 	d.SetId(server.ID)
@@ -158,7 +164,7 @@ func dataSourceServerscomSBMServerRead(d *schema.ResourceData, meta any) error {
 	d.Set("private_ipv4_address", server.PrivateIPv4Address)
 	d.Set("public_ipv4_address", server.PublicIPv4Address)
 	d.Set("lease_start_at", server.LeaseStart)
-	d.Set("scheduled_release_at", server.ScheduledRelease)
+	d.Set("scheduled_release_at", formatScheduledRelease(server.ScheduledRelease))
 	d.Set("type", server.Type)
 	d.Set("oob_ipv4_address", server.OobIPv4Address)
 
@@ -176,8 +182,8 @@ func dataSourceServerscomSBMServerRead(d *schema.ResourceData, meta any) error {
 	d.Set("operating_system_full_name", server.ConfigurationDetails.OperatingSystemFullName)
 
 	d.Set("labels", server.Labels)
-	d.Set("created_at", server.Created)
-	d.Set("updated_at", server.Updated)
+	d.Set("created_at", formatTime(server.Created))
+	d.Set("updated_at", formatTime(server.Updated))
 
 	return nil
 }
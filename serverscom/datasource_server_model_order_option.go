@@ -2,16 +2,15 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomServerModelOrderOption() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomServerModelOrderOptionRead,
+		ReadContext: dataSourceServerscomServerModelOrderOptionRead,
 
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -98,16 +97,15 @@ func dataSourceServerscomServerModelOrderOption() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomServerModelOrderOptionRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomServerModelOrderOptionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("id").(int)
 
 	option, err := client.Locations.GetServerModelOption(ctx, int64(locationID), int64(serverModelID))
 	if err != nil {
-		return fmt.Errorf("Error retrieving server model order option: %s", err.Error())
+		return diag.Errorf("Error retrieving server model order option: %s", err.Error())
 	}
 
 	d.SetId(strconv.Itoa(int(option.ID)))
@@ -135,7 +133,7 @@ func dataSourceServerscomServerModelOrderOptionRead(d *schema.ResourceData, meta
 		driveSlots = append(driveSlots, driveSlot)
 	}
 	if err := d.Set("drive_slots", driveSlots); err != nil {
-		return fmt.Errorf("Error setting drive_slots: %s", err.Error())
+		return diag.Errorf("Error setting drive_slots: %s", err.Error())
 	}
 
 	return nil
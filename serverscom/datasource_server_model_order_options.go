@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomServerModelOrderOptions() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomServerModelOrderOptionsRead,
+		ReadContext: dataSourceServerscomServerModelOrderOptionsRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -95,9 +95,8 @@ func dataSourceServerscomServerModelOrderOptions() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomServerModelOrderOptionsRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomServerModelOrderOptionsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 
@@ -116,14 +115,14 @@ func dataSourceServerscomServerModelOrderOptionsRead(d *schema.ResourceData, met
 
 		hash, err := hashFilter(filter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		id = fmt.Sprintf("%s-%s", id, hash)
 	}
 
 	options, err := collection.Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving server model order options: %s", err.Error())
+		return diag.Errorf("Error retrieving server model order options: %s", err.Error())
 	}
 
 	optionList := make([]map[string]any, 0, len(options))
@@ -147,7 +146,7 @@ func dataSourceServerscomServerModelOrderOptionsRead(d *schema.ResourceData, met
 
 	d.SetId(id)
 	if err := d.Set("server_models", optionList); err != nil {
-		return fmt.Errorf("Error setting server model order options: %s", err.Error())
+		return diag.Errorf("Error setting server model order options: %s", err.Error())
 	}
 
 	return nil
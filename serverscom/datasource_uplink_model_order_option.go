@@ -2,16 +2,15 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomUplinkModelOrderOption() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomUplinkModelOrderOptionRead,
+		ReadContext: dataSourceServerscomUplinkModelOrderOptionRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -46,9 +45,8 @@ func dataSourceServerscomUplinkModelOrderOption() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomUplinkModelOrderOptionRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomUplinkModelOrderOptionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
@@ -56,7 +54,7 @@ func dataSourceServerscomUplinkModelOrderOptionRead(d *schema.ResourceData, meta
 
 	uplink, err := client.Locations.GetUplinkOption(ctx, int64(locationID), int64(serverModelID), int64(uplinkModelID))
 	if err != nil {
-		return fmt.Errorf("Error retrieving uplink model order option: %s", err.Error())
+		return diag.Errorf("Error retrieving uplink model order option: %s", err.Error())
 	}
 
 	d.SetId(strconv.Itoa(int(uplink.ID)))
@@ -5,13 +5,13 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 func dataSourceServerscomUplinkModelOrderOptions() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceServerscomUplinkModelOrderOptionsRead,
+		ReadContext: dataSourceServerscomUplinkModelOrderOptionsRead,
 
 		Schema: map[string]*schema.Schema{
 			"location_id": {
@@ -72,9 +72,8 @@ func dataSourceServerscomUplinkModelOrderOptions() *schema.Resource {
 	}
 }
 
-func dataSourceServerscomUplinkModelOrderOptionsRead(d *schema.ResourceData, meta any) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func dataSourceServerscomUplinkModelOrderOptionsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	locationID := d.Get("location_id").(int)
 	serverModelID := d.Get("server_model_id").(int)
@@ -94,14 +93,14 @@ func dataSourceServerscomUplinkModelOrderOptionsRead(d *schema.ResourceData, met
 
 		hash, err := hashFilter(filter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		id = fmt.Sprintf("%s-%s", id, hash)
 	}
 
 	uplinkModels, err := collection.Collect(ctx)
 	if err != nil {
-		return fmt.Errorf("Error retrieving uplink model order options: %s", err.Error())
+		return diag.Errorf("Error retrieving uplink model order options: %s", err.Error())
 	}
 
 	uplinkList := make([]map[string]any, 0, len(uplinkModels))
@@ -117,7 +116,7 @@ func dataSourceServerscomUplinkModelOrderOptionsRead(d *schema.ResourceData, met
 
 	d.SetId(id)
 	if err := d.Set("uplink_models", uplinkList); err != nil {
-		return fmt.Errorf("Error setting uplink model order options: %s", err.Error())
+		return diag.Errorf("Error setting uplink model order options: %s", err.Error())
 	}
 
 	return nil
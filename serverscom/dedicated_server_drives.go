@@ -0,0 +1,326 @@
+package serverscom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+// raidMinDisks is the minimal number of disks for each supported RAID level
+var raidMinDisks = map[int]int{
+	0:  1,
+	1:  2,
+	5:  3,
+	6:  4,
+	10: 4,
+	50: 6,
+	60: 8,
+}
+
+// customizeDiffDedicatedServerDrives validates drive slots and layouts during plan
+func customizeDiffDedicatedServerDrives(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges("slot", "layout") {
+		return nil
+	}
+
+	// values computed from other resources are validated on apply
+	if !drivesValuesKnown(d) {
+		return nil
+	}
+
+	// slots omitted on create are populated from the server model and validated on apply
+	if d.Id() == "" && !isConfigured(d, cty.GetAttrPath("slot")) {
+		return nil
+	}
+
+	return verifyDrives(d.Get("slot").([]interface{}), d.Get("layout").([]interface{}))
+}
+
+func drivesValuesKnown(d *schema.ResourceDiff) bool {
+	keys := []string{"slot", "layout"}
+
+	for i := range d.Get("slot").([]interface{}) {
+		keys = append(keys, fmt.Sprintf("slot.%d.position", i), fmt.Sprintf("slot.%d.drive_model", i), fmt.Sprintf("slot.%d.drive_model_id", i))
+	}
+
+	for i, layoutSchema := range d.Get("layout").([]interface{}) {
+		keys = append(keys, fmt.Sprintf("layout.%d.slot_positions", i), fmt.Sprintf("layout.%d.raid", i))
+
+		if layout, ok := layoutSchema.(map[string]interface{}); ok {
+			for j := range layout["partition"].([]interface{}) {
+				keys = append(keys, fmt.Sprintf("layout.%d.partition.%d.fill", i, j))
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if !d.NewValueKnown(key) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// verifyDrives checks the slot and layout blocks are consistent with each other
+func verifyDrives(slots []interface{}, layouts []interface{}) error {
+	var errs []error
+
+	// filled maps declared slot positions to whether the slot has a drive
+	filled := make(map[int]bool)
+
+	for i, slotSchema := range slots {
+		slot := slotSchema.(map[string]interface{})
+		position := slot["position"].(int)
+
+		if _, ok := filled[position]; ok {
+			errs = append(errs, fmt.Errorf("slot.%d: slot with position %d is declared more than once", i, position))
+		}
+
+		driveModelID, _ := slot["drive_model_id"].(int)
+		filled[position] = slot["drive_model"].(string) != "" || driveModelID != 0
+	}
+
+	if len(slots) == 0 {
+		errs = append(errs, fmt.Errorf("at least one slot must be specified"))
+	} else if !filled[0] {
+		errs = append(errs, fmt.Errorf("slot with position 0 must be filled"))
+	}
+
+	// used maps slot positions to the index of the layout using it
+	used := make(map[int]int)
+
+	for i, layoutSchema := range layouts {
+		layout := layoutSchema.(map[string]interface{})
+		positions := expandIntList(layout["slot_positions"].([]interface{}))
+
+		for _, position := range positions {
+			if hasDrive, ok := filled[position]; !ok {
+				errs = append(errs, fmt.Errorf("layout.%d: slot with position %d isn't declared", i, position))
+			} else if !hasDrive {
+				errs = append(errs, fmt.Errorf("layout.%d: slot with position %d has no drive model", i, position))
+			}
+
+			if j, ok := used[position]; ok {
+				if i == j {
+					errs = append(errs, fmt.Errorf("layout.%d: slot with position %d is listed more than once", i, position))
+				} else {
+					errs = append(errs, fmt.Errorf("layout.%d: slot with position %d is already used by layout.%d", i, position, j))
+				}
+				continue
+			}
+
+			used[position] = i
+		}
+
+		if err := verifyRaid(layout["raid"].(int), len(positions)); err != nil {
+			errs = append(errs, fmt.Errorf("layout.%d: %s", i, err))
+		}
+
+		var fillCount int
+		for _, partitionSchema := range layout["partition"].([]interface{}) {
+			if partitionSchema.(map[string]interface{})["fill"].(bool) {
+				fillCount++
+			}
+		}
+
+		if fillCount > 1 {
+			errs = append(errs, fmt.Errorf("layout.%d: only one partition can have fill enabled, got %d", i, fillCount))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyRaid checks the RAID level fits the number of disks
+func verifyRaid(raid int, disks int) error {
+	minDisks, ok := raidMinDisks[raid]
+	if !ok {
+		return fmt.Errorf("unsupported raid level %d", raid)
+	}
+
+	if disks < minDisks {
+		return fmt.Errorf("raid %d requires at least %d disks, got %d", raid, minDisks, disks)
+	}
+
+	if raid == 10 && disks%2 != 0 {
+		return fmt.Errorf("raid 10 requires an even number of disks, got %d", disks)
+	}
+
+	return nil
+}
+
+// getDefaultSlots returns the drive slots the server model comes with, empty slots are skipped
+func getDefaultSlots(ctx context.Context, cache *Cache, locationID int64, serverModelID int64) ([]scgo.DedicatedServerSlotInput, error) {
+	serverModel, err := cache.ServerModel(ctx, locationID, serverModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []scgo.DedicatedServerSlotInput
+	for _, slot := range serverModel.DriveSlots {
+		if slot.DriveModelID == 0 {
+			continue
+		}
+
+		driveModelID := slot.DriveModelID
+		slots = append(slots, scgo.DedicatedServerSlotInput{
+			Position:     slot.Position,
+			DriveModelID: &driveModelID,
+		})
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Position < slots[j].Position
+	})
+
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("server model (%d) comes without drives, at least one slot must be specified", serverModelID)
+	}
+
+	return slots, nil
+}
+
+// defaultLayouts returns a layout with the root partition in RAID 1 across the first two drives of the same model,
+// or on the first drive when there are no identical drives
+func defaultLayouts(slots []scgo.DedicatedServerSlotInput) []scgo.DedicatedServerLayoutInput {
+	fs := "ext4"
+	layout := scgo.DedicatedServerLayoutInput{
+		Partitions: []scgo.DedicatedServerLayoutPartitionInput{
+			{Target: "/", Size: 1, Fs: &fs, Fill: true},
+		},
+	}
+
+	slots = append([]scgo.DedicatedServerSlotInput{}, slots...)
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Position < slots[j].Position
+	})
+
+	// first maps drive models to the position of the first slot with the model
+	first := make(map[int64]int)
+
+	for _, slot := range slots {
+		if slot.DriveModelID == nil {
+			continue
+		}
+
+		if position, ok := first[*slot.DriveModelID]; ok {
+			raid := 1
+			layout.SlotPositions = []int{position, slot.Position}
+			layout.Raid = &raid
+
+			return []scgo.DedicatedServerLayoutInput{layout}
+		}
+
+		first[*slot.DriveModelID] = slot.Position
+	}
+
+	for _, slot := range slots {
+		if slot.DriveModelID != nil {
+			layout.SlotPositions = []int{slot.Position}
+
+			return []scgo.DedicatedServerLayoutInput{layout}
+		}
+	}
+
+	return nil
+}
+
+// flattenSlots converts slots input to the slot attribute value
+func flattenSlots(slots []scgo.DedicatedServerSlotInput) []interface{} {
+	result := make([]interface{}, 0, len(slots))
+
+	for _, slot := range slots {
+		var driveModelID int
+		if slot.DriveModelID != nil {
+			driveModelID = int(*slot.DriveModelID)
+		}
+
+		result = append(result, map[string]interface{}{
+			"position":       slot.Position,
+			"drive_model":    "",
+			"drive_model_id": driveModelID,
+		})
+	}
+
+	return result
+}
+
+// flattenLayouts converts layouts input to the layout attribute value
+func flattenLayouts(layouts []scgo.DedicatedServerLayoutInput) []interface{} {
+	result := make([]interface{}, 0, len(layouts))
+
+	for _, layout := range layouts {
+		var raid int
+		if layout.Raid != nil {
+			raid = *layout.Raid
+		}
+
+		partitions := make([]interface{}, 0, len(layout.Partitions))
+		for _, partition := range layout.Partitions {
+			var fs string
+			if partition.Fs != nil {
+				fs = *partition.Fs
+			}
+
+			partitions = append(partitions, map[string]interface{}{
+				"target": partition.Target,
+				"size":   partition.Size,
+				"fill":   partition.Fill,
+				"fs":     fs,
+			})
+		}
+
+		slotPositions := make([]interface{}, 0, len(layout.SlotPositions))
+		for _, position := range layout.SlotPositions {
+			slotPositions = append(slotPositions, position)
+		}
+
+		result = append(result, map[string]interface{}{
+			"slot_positions": slotPositions,
+			"raid":           raid,
+			"partition":      partitions,
+		})
+	}
+
+	return result
+}
+
+// hostLayoutsInput converts the layouts the server was installed with to the layouts input
+func hostLayoutsInput(layouts []scgo.HostLayout) []scgo.DedicatedServerLayoutInput {
+	result := make([]scgo.DedicatedServerLayoutInput, 0, len(layouts))
+
+	for _, layout := range layouts {
+		layoutInput := scgo.DedicatedServerLayoutInput{
+			SlotPositions: layout.SlotPositions,
+			Raid:          layout.Raid,
+		}
+
+		for _, partition := range layout.Partitions {
+			layoutInput.Partitions = append(layoutInput.Partitions, scgo.DedicatedServerLayoutPartitionInput{
+				Target: partition.Target,
+				Size:   partition.Size,
+				Fs:     partition.Fs,
+				Fill:   partition.Fill,
+			})
+		}
+
+		result = append(result, layoutInput)
+	}
+
+	return result
+}
+
+// suppressFilledPartitionSize suppresses the size diff of partitions filling the unused space,
+// the size is the minimal one for them and the api may report the actual size
+func suppressFilledPartitionSize(k, old, new string, d *schema.ResourceData) bool {
+	fillKey := strings.TrimSuffix(k, "size") + "fill"
+
+	return d.Get(fillKey).(bool)
+}
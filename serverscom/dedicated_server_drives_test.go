@@ -0,0 +1,215 @@
+package serverscom
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func testSlot(position int, driveModel string) interface{} {
+	return map[string]interface{}{"position": position, "drive_model": driveModel}
+}
+
+func testLayout(raid int, positions []int, fills ...bool) interface{} {
+	slotPositions := make([]interface{}, len(positions))
+	for i, position := range positions {
+		slotPositions[i] = position
+	}
+
+	partitions := make([]interface{}, len(fills))
+	for i, fill := range fills {
+		partitions[i] = map[string]interface{}{"target": "/", "size": 1, "fill": fill, "fs": "ext4"}
+	}
+
+	return map[string]interface{}{"slot_positions": slotPositions, "raid": raid, "partition": partitions}
+}
+
+func TestVerifyDrives(t *testing.T) {
+	ssd := "480 GB SSD SATA"
+	fourSlots := []interface{}{testSlot(0, ssd), testSlot(1, ssd), testSlot(2, ssd), testSlot(3, ssd)}
+
+	cases := []struct {
+		name    string
+		slots   []interface{}
+		layouts []interface{}
+		err     string
+	}{
+		{
+			name:    "valid",
+			slots:   fourSlots,
+			layouts: []interface{}{testLayout(10, []int{0, 1, 2, 3}, false, true)},
+		},
+		{
+			name:    "single disk without raid",
+			slots:   []interface{}{testSlot(0, ssd)},
+			layouts: []interface{}{testLayout(0, []int{0}, true)},
+		},
+		{
+			name: "no slots",
+			err:  "at least one slot must be specified",
+		},
+		{
+			name:  "empty first slot",
+			slots: []interface{}{testSlot(0, ""), testSlot(1, ssd)},
+			err:   "slot with position 0 must be filled",
+		},
+		{
+			name:  "duplicated slot",
+			slots: []interface{}{testSlot(0, ssd), testSlot(0, ssd)},
+			err:   "slot.1: slot with position 0 is declared more than once",
+		},
+		{
+			name:    "undeclared slot",
+			slots:   []interface{}{testSlot(0, ssd)},
+			layouts: []interface{}{testLayout(1, []int{0, 1})},
+			err:     "layout.0: slot with position 1 isn't declared",
+		},
+		{
+			name:    "slot without drive",
+			slots:   []interface{}{testSlot(0, ssd), testSlot(1, "")},
+			layouts: []interface{}{testLayout(1, []int{0, 1})},
+			err:     "layout.0: slot with position 1 has no drive model",
+		},
+		{
+			name:    "slot used by two layouts",
+			slots:   fourSlots,
+			layouts: []interface{}{testLayout(1, []int{0, 1}), testLayout(1, []int{1, 2})},
+			err:     "layout.1: slot with position 1 is already used by layout.0",
+		},
+		{
+			name:    "raid 5 with two disks",
+			slots:   fourSlots,
+			layouts: []interface{}{testLayout(5, []int{0, 1})},
+			err:     "layout.0: raid 5 requires at least 3 disks, got 2",
+		},
+		{
+			name:    "raid 10 with odd disks",
+			slots:   append(fourSlots, testSlot(4, ssd)),
+			layouts: []interface{}{testLayout(10, []int{0, 1, 2, 3, 4})},
+			err:     "layout.0: raid 10 requires an even number of disks, got 5",
+		},
+		{
+			name:    "several fill partitions",
+			slots:   fourSlots,
+			layouts: []interface{}{testLayout(0, []int{0}, true, true)},
+			err:     "layout.0: only one partition can have fill enabled, got 2",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := verifyDrives(c.slots, c.layouts)
+
+			if c.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("expected error %q, got %v", c.err, err)
+			}
+		})
+	}
+}
+
+func testSlotInput(position int, driveModelID int64) scgo.DedicatedServerSlotInput {
+	return scgo.DedicatedServerSlotInput{Position: position, DriveModelID: &driveModelID}
+}
+
+func TestGetDefaultSlots(t *testing.T) {
+	cache := NewCache(nil, DefaultCacheConfig())
+	cache.add("locations/1/server_models/1", &scgo.ServerModelOptionDetail{
+		DriveSlots: []scgo.ServerModelDriveSlot{
+			{Position: 2, DriveModelID: 5},
+			{Position: 1},
+			{Position: 0, DriveModelID: 5},
+		},
+	})
+	cache.add("locations/1/server_models/2", &scgo.ServerModelOptionDetail{
+		DriveSlots: []scgo.ServerModelDriveSlot{{Position: 0}},
+	})
+
+	slots, err := getDefaultSlots(context.Background(), cache, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := []scgo.DedicatedServerSlotInput{testSlotInput(0, 5), testSlotInput(2, 5)}; !reflect.DeepEqual(slots, expected) {
+		t.Fatalf("expected filled slots ordered by position, got %v", slots)
+	}
+
+	if _, err := getDefaultSlots(context.Background(), cache, 1, 2); err == nil {
+		t.Fatal("expected server model without drives to be rejected")
+	}
+}
+
+func TestDefaultLayouts(t *testing.T) {
+	cases := []struct {
+		name      string
+		slots     []scgo.DedicatedServerSlotInput
+		positions []int
+		raid      *int
+	}{
+		{
+			name:      "identical drives",
+			slots:     []scgo.DedicatedServerSlotInput{testSlotInput(3, 2), testSlotInput(0, 1), testSlotInput(1, 2), testSlotInput(2, 1)},
+			positions: []int{0, 2},
+			raid:      &[]int{1}[0],
+		},
+		{
+			name:      "different drives",
+			slots:     []scgo.DedicatedServerSlotInput{testSlotInput(1, 2), testSlotInput(0, 1)},
+			positions: []int{0},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			layouts := defaultLayouts(c.slots)
+			if len(layouts) != 1 {
+				t.Fatalf("expected one layout, got %d", len(layouts))
+			}
+
+			if !reflect.DeepEqual(layouts[0].SlotPositions, c.positions) || !reflect.DeepEqual(layouts[0].Raid, c.raid) {
+				t.Fatalf("unexpected layout: %v", layouts[0])
+			}
+
+			if err := verifyDrives(flattenSlots(c.slots), flattenLayouts(layouts)); err != nil {
+				t.Fatalf("expected the default layout to be valid, got %s", err)
+			}
+		})
+	}
+}
+
+func TestHostLayoutsInput(t *testing.T) {
+	raid := 1
+	layouts := flattenLayouts(hostLayoutsInput([]scgo.HostLayout{
+		{
+			SlotPositions: []int{0, 1},
+			Raid:          &raid,
+			Partitions:    []scgo.HostLayoutPartition{{Target: "/", Size: 1, Fs: &[]string{"ext4"}[0], Fill: true}},
+		},
+		{
+			SlotPositions: []int{2},
+			Partitions:    []scgo.HostLayoutPartition{{Target: "swap", Size: 4096}},
+		},
+	}))
+
+	expected := []interface{}{
+		testLayout(1, []int{0, 1}, true),
+		map[string]interface{}{
+			"slot_positions": []interface{}{2},
+			"raid":           0,
+			"partition":      []interface{}{map[string]interface{}{"target": "swap", "size": 4096, "fill": false, "fs": ""}},
+		},
+	}
+
+	if !reflect.DeepEqual(layouts, expected) {
+		t.Fatalf("expected %v, got %v", expected, layouts)
+	}
+}
@@ -0,0 +1,289 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+// dedicatedServerFeature switches a single feature of a dedicated server through the features api
+type dedicatedServerFeature struct {
+	attribute string
+	name      string
+	// settings are attributes of the features block used on activation, changing them reactivates the feature
+	settings   []string
+	activate   func(ctx context.Context, client *scgo.Client, id string, features map[string]interface{}) error
+	deactivate func(ctx context.Context, client *scgo.Client, id string) error
+}
+
+var dedicatedServerFeatures = []dedicatedServerFeature{
+	{
+		attribute: "rescue_mode",
+		name:      "host_rescue_mode",
+		settings:  []string{"rescue_mode_ssh_key_fingerprints"},
+		activate: func(ctx context.Context, client *scgo.Client, id string, features map[string]interface{}) error {
+			input := scgo.HostRescueModeInput{AuthMethods: []string{"password"}}
+
+			if fingerprints := expandedStringList(features["rescue_mode_ssh_key_fingerprints"].([]interface{})); len(fingerprints) > 0 {
+				input.AuthMethods = []string{"ssh_key"}
+				input.SSHKeyFingerprints = fingerprints
+			}
+
+			_, err := client.Hosts.ActivateHostRescueModeFeature(ctx, id, input)
+			return err
+		},
+		deactivate: func(ctx context.Context, client *scgo.Client, id string) error {
+			_, err := client.Hosts.DeactivateHostRescueModeFeature(ctx, id)
+			return err
+		},
+	},
+	{
+		attribute: "oob_public_access",
+		name:      "oob_public_access",
+		activate: func(ctx context.Context, client *scgo.Client, id string, features map[string]interface{}) error {
+			_, err := client.Hosts.ActivateOobPublicAccessFeature(ctx, id)
+			return err
+		},
+		deactivate: func(ctx context.Context, client *scgo.Client, id string) error {
+			_, err := client.Hosts.DeactivateOobPublicAccessFeature(ctx, id)
+			return err
+		},
+	},
+	{
+		attribute: "private_ipxe_boot",
+		name:      "private_ipxe_boot",
+		settings:  []string{"ipxe_config"},
+		activate: func(ctx context.Context, client *scgo.Client, id string, features map[string]interface{}) error {
+			input := scgo.PrivateIPXEBootInput{IPXEConfig: features["ipxe_config"].(string)}
+
+			_, err := client.Hosts.ActivatePrivateIPXEBootFeature(ctx, id, input)
+			return err
+		},
+		deactivate: func(ctx context.Context, client *scgo.Client, id string) error {
+			_, err := client.Hosts.DeactivatePrivateIPXEBootFeature(ctx, id)
+			return err
+		},
+	},
+	{
+		attribute: "disaggregated_public_ports",
+		name:      "disaggregated_public_ports",
+		activate: func(ctx context.Context, client *scgo.Client, id string, features map[string]interface{}) error {
+			_, err := client.Hosts.ActivateDisaggregatedPublicPortsFeature(ctx, id)
+			return err
+		},
+		deactivate: func(ctx context.Context, client *scgo.Client, id string) error {
+			_, err := client.Hosts.DeactivateDisaggregatedPublicPortsFeature(ctx, id)
+			return err
+		},
+	},
+	{
+		attribute: "no_public_network",
+		name:      "no_public_network",
+		activate: func(ctx context.Context, client *scgo.Client, id string, features map[string]interface{}) error {
+			_, err := client.Hosts.ActivateNoPublicNetworkFeature(ctx, id)
+			return err
+		},
+		deactivate: func(ctx context.Context, client *scgo.Client, id string) error {
+			_, err := client.Hosts.DeactivateNoPublicNetworkFeature(ctx, id)
+			return err
+		},
+	},
+}
+
+func dedicatedServerFeaturesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"rescue_mode": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+				"rescue_mode_ssh_key_fingerprints": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"oob_public_access": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+				"private_ipxe_boot": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+				"ipxe_config": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.NoZeroValues,
+				},
+				"disaggregated_public_ports": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+				"no_public_network": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+			},
+		},
+	}
+}
+
+// expandDedicatedServerFeatures returns the features block, features missing from the value are disabled.
+// The block is computed, so removing it from the configuration keeps the features as they are,
+// they are deactivated only when set to false explicitly.
+func expandDedicatedServerFeatures(v interface{}) map[string]interface{} {
+	features := map[string]interface{}{
+		"rescue_mode_ssh_key_fingerprints": []interface{}{},
+		"ipxe_config":                      "",
+	}
+	for _, feature := range dedicatedServerFeatures {
+		features[feature.attribute] = false
+	}
+
+	if list, ok := v.([]interface{}); ok && len(list) > 0 && list[0] != nil {
+		for key, value := range list[0].(map[string]interface{}) {
+			features[key] = value
+		}
+	}
+
+	return features
+}
+
+// flattenDedicatedServerFeatures returns the features block for the feature statuses reported by the api.
+// Activation settings aren't reported, they are kept from the previous value.
+func flattenDedicatedServerFeatures(previous interface{}, statuses []scgo.DedicatedServerFeature) []interface{} {
+	features := expandDedicatedServerFeatures(previous)
+
+	for _, feature := range dedicatedServerFeatures {
+		for _, status := range statuses {
+			if status.Name == feature.name {
+				features[feature.attribute] = status.Status == "activated" || status.Status == "activation"
+			}
+		}
+	}
+
+	return []interface{}{features}
+}
+
+// validateDedicatedServerFeatures checks settings required for activation of the enabled features
+func validateDedicatedServerFeatures(features map[string]interface{}) error {
+	if features["private_ipxe_boot"].(bool) && features["ipxe_config"].(string) == "" {
+		return fmt.Errorf("ipxe_config is required to enable private_ipxe_boot")
+	}
+
+	return nil
+}
+
+// customizeDiffDedicatedServerFeatures checks the enabled features have the settings required for activation
+func customizeDiffDedicatedServerFeatures(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("features") || !d.NewValueKnown("features.0.ipxe_config") {
+		return nil
+	}
+
+	return validateDedicatedServerFeatures(expandDedicatedServerFeatures(d.Get("features")))
+}
+
+// applyDedicatedServerFeatures switches features which differ between old and new and waits for each of them
+// to finish the transition. Enabled features with changed settings are reactivated.
+func applyDedicatedServerFeatures(ctx context.Context, d *schema.ResourceData, meta interface{}, old, new map[string]interface{}, timeoutKey string) error {
+	if err := validateDedicatedServerFeatures(new); err != nil {
+		return err
+	}
+
+	client := meta.(*ProviderMeta).Client
+
+	for _, feature := range dedicatedServerFeatures {
+		wasEnabled, enabled := old[feature.attribute].(bool), new[feature.attribute].(bool)
+
+		settingsChanged := false
+		for _, key := range feature.settings {
+			if !reflect.DeepEqual(old[key], new[key]) {
+				settingsChanged = true
+			}
+		}
+
+		if wasEnabled && (!enabled || settingsChanged) {
+			log.Printf("[INFO] Deactivating %s feature of dedicated server (%s)", feature.name, d.Id())
+
+			if err := feature.deactivate(ctx, client, d.Id()); err != nil {
+				return fmt.Errorf("Error deactivating %s feature of dedicated server (%s): %s", feature.name, d.Id(), describeAPIError(err))
+			}
+
+			if err := waitForDedicatedServerFeature(ctx, d, meta, feature.name, "deactivated", timeoutKey); err != nil {
+				return err
+			}
+		}
+
+		if enabled && (!wasEnabled || settingsChanged) {
+			log.Printf("[INFO] Activating %s feature of dedicated server (%s)", feature.name, d.Id())
+
+			if err := feature.activate(ctx, client, d.Id(), new); err != nil {
+				return fmt.Errorf("Error activating %s feature of dedicated server (%s): %s", feature.name, d.Id(), describeAPIError(err))
+			}
+
+			if err := waitForDedicatedServerFeature(ctx, d, meta, feature.name, "activated", timeoutKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func waitForDedicatedServerFeature(ctx context.Context, d *schema.ResourceData, meta interface{}, name, target, timeoutKey string) error {
+	log.Printf("[INFO] Waiting for %s feature of dedicated server (%s) to have status of %s", name, d.Id(), target)
+
+	client := meta.(*ProviderMeta).Client
+
+	// the previous status is pending too, until the api picks up the request
+	var pending []string
+	for _, status := range []string{"activation", "deactivation", "activated", "deactivated"} {
+		if status != target {
+			pending = append(pending, status)
+		}
+	}
+
+	stateConf := &retry.StateChangeConf{
+		Pending: pending,
+		Target:  []string{target},
+		Refresh: func() (interface{}, string, error) {
+			features, err := client.Hosts.DedicatedServerFeatures(d.Id()).Collect(ctx)
+			if err != nil {
+				return nil, "", err
+			}
+
+			for _, feature := range features {
+				if feature.Name == name {
+					return feature, feature.Status, nil
+				}
+			}
+
+			return nil, "", fmt.Errorf("feature %s isn't available for dedicated server (%s)", name, d.Id())
+		},
+		Timeout:      d.Timeout(timeoutKey),
+		PollInterval: waiterDelay(10 * time.Second),
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("Error waiting for %s feature of dedicated server (%s) to be %s: %s", name, d.Id(), target, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package serverscom
+
+import (
+	"testing"
+
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func TestFlattenDedicatedServerFeatures(t *testing.T) {
+	previous := []interface{}{
+		map[string]interface{}{
+			"rescue_mode":                      false,
+			"rescue_mode_ssh_key_fingerprints": []interface{}{"aa:bb"},
+			"oob_public_access":                true,
+			"private_ipxe_boot":                false,
+			"ipxe_config":                      "",
+			"disaggregated_public_ports":       false,
+			"no_public_network":                false,
+		},
+	}
+
+	features := flattenDedicatedServerFeatures(previous, []scgo.DedicatedServerFeature{
+		{Name: "host_rescue_mode", Status: "activation"},
+		{Name: "oob_public_access", Status: "deactivated"},
+		{Name: "no_public_network", Status: "incompatible"},
+	})[0].(map[string]interface{})
+
+	if features["rescue_mode"] != true {
+		t.Errorf("expected rescue_mode being activated to be enabled")
+	}
+	if features["oob_public_access"] != false {
+		t.Errorf("expected deactivated oob_public_access to be disabled")
+	}
+	if features["no_public_network"] != false {
+		t.Errorf("expected incompatible no_public_network to be disabled")
+	}
+	if fingerprints := features["rescue_mode_ssh_key_fingerprints"].([]interface{}); len(fingerprints) != 1 {
+		t.Errorf("expected activation settings to be kept, got %v", fingerprints)
+	}
+}
+
+func TestValidateDedicatedServerFeatures(t *testing.T) {
+	features := expandDedicatedServerFeatures(nil)
+	if err := validateDedicatedServerFeatures(features); err != nil {
+		t.Fatalf("unexpected error for disabled features: %s", err)
+	}
+
+	features["private_ipxe_boot"] = true
+	if err := validateDedicatedServerFeatures(features); err == nil {
+		t.Fatalf("expected private_ipxe_boot without ipxe_config to be rejected")
+	}
+
+	features["ipxe_config"] = "#!ipxe"
+	if err := validateDedicatedServerFeatures(features); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,260 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+// dedicatedServerReinstallAttributes can be changed in place by reinstalling the operating system
+var dedicatedServerReinstallAttributes = []string{"operating_system", "operating_system_id", "layout", "ssh_key_fingerprints"}
+
+// resourceChanges is the part of schema.ResourceData and schema.ResourceDiff used to find the reinstall changes
+type resourceChanges interface {
+	Get(key string) interface{}
+	GetChange(key string) (interface{}, interface{})
+	HasChange(key string) bool
+}
+
+// dedicatedServerReinstallChanges returns the changed attributes which require reinstalling the operating system.
+// The configured layout of a server with an unknown layout is stored without the reinstall.
+func dedicatedServerReinstallChanges(d resourceChanges) []string {
+	var changed []string
+	for _, key := range dedicatedServerReinstallAttributes {
+		if !d.HasChange(key) || (key == "layout" && layoutUnknown(d)) {
+			continue
+		}
+
+		changed = append(changed, key)
+	}
+
+	return changed
+}
+
+// layoutUnknown reports whether the api didn't report the layout of the server and there's none in the state
+func layoutUnknown(d resourceChanges) bool {
+	oldLayouts, _ := d.GetChange("layout")
+
+	return !d.Get("layout_reported").(bool) && len(oldLayouts.([]interface{})) == 0
+}
+
+// customizeDiffDedicatedServerReinstall refuses changes wiping the server unless reinstall_on_change is set,
+// slot and layout values differing from the ones read back only in what the api fills in aren't changes
+func customizeDiffDedicatedServerReinstall(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.HasChange("slot") {
+		oldSlots, newSlots := d.GetChange("slot")
+		if !slotsEquivalent(oldSlots.([]interface{}), newSlots.([]interface{})) {
+			return fmt.Errorf("slot can't be changed on an existing dedicated server, the drive slots are fixed when the server is ordered, revert slot or replace the server")
+		}
+
+		if err := d.Clear("slot"); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("layout") && !layoutUnknown(d) {
+		oldLayouts, newLayouts := d.GetChange("layout")
+		if layoutsEquivalent(oldLayouts.([]interface{}), newLayouts.([]interface{})) {
+			if err := d.Clear("layout"); err != nil {
+				return err
+			}
+		}
+	}
+
+	changed := dedicatedServerReinstallChanges(d)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if !d.Get("reinstall_on_change").(bool) {
+		return reinstallNotAllowedError(changed)
+	}
+
+	// the other form of the operating system is known after the reinstall only
+	if d.HasChanges("operating_system", "operating_system_id") {
+		computedKey := "operating_system_id"
+		if isConfigured(d, cty.GetAttrPath("operating_system_id")) {
+			computedKey = "operating_system"
+		}
+
+		if err := d.SetNewComputed(computedKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// slotsEquivalent reports whether the configured slots describe the slots read back,
+// drive models are compared by id only as the api doesn't report their names
+func slotsEquivalent(current, configured []interface{}) bool {
+	if len(current) != len(configured) {
+		return false
+	}
+
+	driveModelIDs := make(map[int]int, len(current))
+	for _, slotSchema := range current {
+		slot := slotSchema.(map[string]interface{})
+		driveModelIDs[slot["position"].(int)] = slot["drive_model_id"].(int)
+	}
+
+	for _, slotSchema := range configured {
+		slot := slotSchema.(map[string]interface{})
+
+		currentID, ok := driveModelIDs[slot["position"].(int)]
+		if !ok {
+			return false
+		}
+
+		if driveModelID, _ := slot["drive_model_id"].(int); driveModelID != 0 && currentID != 0 && driveModelID != currentID {
+			return false
+		}
+	}
+
+	return true
+}
+
+// layoutsEquivalent reports whether the configured layouts describe the layouts read back,
+// sizes of filling partitions and file systems left unset in the configuration are ignored
+func layoutsEquivalent(current, configured []interface{}) bool {
+	if len(current) != len(configured) {
+		return false
+	}
+
+	for i := range current {
+		currentLayout := current[i].(map[string]interface{})
+		configuredLayout := configured[i].(map[string]interface{})
+
+		if currentLayout["raid"].(int) != configuredLayout["raid"].(int) {
+			return false
+		}
+
+		currentPositions := expandIntList(currentLayout["slot_positions"].([]interface{}))
+		configuredPositions := expandIntList(configuredLayout["slot_positions"].([]interface{}))
+		sort.Ints(currentPositions)
+		sort.Ints(configuredPositions)
+		if !reflect.DeepEqual(currentPositions, configuredPositions) {
+			return false
+		}
+
+		currentPartitions := currentLayout["partition"].([]interface{})
+		configuredPartitions := configuredLayout["partition"].([]interface{})
+		if len(currentPartitions) != len(configuredPartitions) {
+			return false
+		}
+
+		for j := range currentPartitions {
+			currentPartition := currentPartitions[j].(map[string]interface{})
+			configuredPartition := configuredPartitions[j].(map[string]interface{})
+
+			fill := configuredPartition["fill"].(bool)
+			switch {
+			case currentPartition["target"] != configuredPartition["target"]:
+				return false
+			case currentPartition["fill"].(bool) != fill:
+				return false
+			case !fill && currentPartition["size"].(int) != configuredPartition["size"].(int):
+				return false
+			case configuredPartition["fs"].(string) != "" && currentPartition["fs"] != configuredPartition["fs"]:
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func reinstallNotAllowedError(changed []string) error {
+	return fmt.Errorf(
+		"changing %s reinstalls the operating system and wipes all data on the dedicated server, set reinstall_on_change to true to allow it",
+		strings.Join(changed, ", "),
+	)
+}
+
+// reinstallOperatingSystemID returns the id of the configured operating system. When the configuration omits it,
+// e.g. for imported servers, the installed operating system from the state is reinstalled.
+func reinstallOperatingSystemID(ctx context.Context, d *schema.ResourceData, cache *Cache) (*int64, diag.Diagnostics) {
+	operatingSystemRef := getOptionRef(d, "operating_system", "operating_system_id")
+	if operatingSystemRef.IsEmpty() {
+		if id := int64(d.Get("operating_system_id").(int)); id != 0 {
+			return &id, nil
+		}
+
+		return nil, nil
+	}
+
+	locationID := int64(d.Get("location_id").(int))
+	serverModelID := int64(d.Get("server_model_id").(int))
+
+	operatingSystem, err := getOperatingSystem(ctx, cache, locationID, serverModelID, operatingSystemRef)
+	if err != nil {
+		return nil, optionDiagnostics(err, operatingSystemRef.Path)
+	}
+
+	return &operatingSystem.ID, nil
+}
+
+// reinstallDedicatedServer reinstalls the operating system with the configured drive layout and ssh keys
+// and waits for the server to become active again
+func reinstallDedicatedServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if !d.Get("reinstall_on_change").(bool) {
+		return diag.FromErr(reinstallNotAllowedError(dedicatedServerReinstallChanges(d)))
+	}
+
+	client := meta.(*ProviderMeta).Client
+	cache := meta.(*ProviderMeta).Cache
+
+	input := scgo.OperatingSystemReinstallInput{
+		Hostname: d.Get("hostname").(string),
+	}
+
+	operatingSystemID, diags := reinstallOperatingSystemID(ctx, d, cache)
+	if diags.HasError() {
+		return diags
+	}
+	input.OperatingSystemID = operatingSystemID
+
+	if val, ok := d.GetOk("ssh_key_fingerprints"); ok {
+		input.SSHKeyFingerprints = expandedStringList(val.([]interface{}))
+	}
+
+	for _, layout := range getLayouts(d) {
+		reinstallLayout := scgo.OperatingSystemReinstallLayoutInput{
+			SlotPositions: layout.SlotPositions,
+			Raid:          layout.Raid,
+		}
+
+		for _, partition := range layout.Partitions {
+			reinstallLayout.Partitions = append(reinstallLayout.Partitions, scgo.OperatingSystemReinstallPartitionInput{
+				Target: partition.Target,
+				Size:   partition.Size,
+				Fs:     partition.Fs,
+				Fill:   partition.Fill,
+			})
+		}
+
+		input.Drives.Layout = append(input.Drives.Layout, reinstallLayout)
+	}
+
+	if _, err := client.Hosts.ReinstallOperatingSystemForDedicatedServer(ctx, d.Id(), input); err != nil {
+		return diag.Errorf("Error reinstalling dedicated server (%s): %s", d.Id(), err)
+	}
+
+	_, err := waitForDedicatedServerAttribute(ctx, d, "active", []string{"init", "pending"}, "status", meta, schema.TimeoutUpdate)
+	if err != nil {
+		return diag.Errorf("Error waiting for dedicated server (%s) to become ready after reinstall: %s", d.Id(), err)
+	}
+
+	return nil
+}
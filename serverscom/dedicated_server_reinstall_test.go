@@ -0,0 +1,225 @@
+package serverscom
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func testReadSlot(position int, driveModelID int) interface{} {
+	return map[string]interface{}{"position": position, "drive_model": "", "drive_model_id": driveModelID}
+}
+
+func TestSlotsEquivalent(t *testing.T) {
+	current := []interface{}{testReadSlot(0, 7), testReadSlot(1, 7)}
+
+	cases := []struct {
+		name       string
+		configured []interface{}
+		equivalent bool
+	}{
+		{
+			name:       "same slots",
+			configured: []interface{}{testReadSlot(0, 7), testReadSlot(1, 7)},
+			equivalent: true,
+		},
+		{
+			name: "drive model by name",
+			configured: []interface{}{
+				map[string]interface{}{"position": 1, "drive_model": "480 GB SSD SATA", "drive_model_id": 7},
+				map[string]interface{}{"position": 0, "drive_model": "480 GB SSD SATA", "drive_model_id": 7},
+			},
+			equivalent: true,
+		},
+		{
+			name:       "other drive model",
+			configured: []interface{}{testReadSlot(0, 7), testReadSlot(1, 8)},
+		},
+		{
+			name:       "other position",
+			configured: []interface{}{testReadSlot(0, 7), testReadSlot(2, 7)},
+		},
+		{
+			name:       "added slot",
+			configured: []interface{}{testReadSlot(0, 7), testReadSlot(1, 7), testReadSlot(2, 7)},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if equivalent := slotsEquivalent(current, c.configured); equivalent != c.equivalent {
+				t.Fatalf("expected %t, got %t", c.equivalent, equivalent)
+			}
+		})
+	}
+}
+
+func testReadLayout(raid int, positions []interface{}, partitions ...interface{}) interface{} {
+	return map[string]interface{}{"slot_positions": positions, "raid": raid, "partition": partitions}
+}
+
+func testPartition(target string, size int, fill bool, fs string) interface{} {
+	return map[string]interface{}{"target": target, "size": size, "fill": fill, "fs": fs}
+}
+
+func TestLayoutsEquivalent(t *testing.T) {
+	current := []interface{}{
+		testReadLayout(1, []interface{}{0, 1}, testPartition("/boot", 500, false, "ext4"), testPartition("/", 476000, true, "ext4")),
+	}
+
+	cases := []struct {
+		name       string
+		configured []interface{}
+		equivalent bool
+	}{
+		{
+			name: "same layout",
+			configured: []interface{}{
+				testReadLayout(1, []interface{}{0, 1}, testPartition("/boot", 500, false, "ext4"), testPartition("/", 476000, true, "ext4")),
+			},
+			equivalent: true,
+		},
+		{
+			name: "filled size, unset fs and slot order",
+			configured: []interface{}{
+				testReadLayout(1, []interface{}{1, 0}, testPartition("/boot", 500, false, ""), testPartition("/", 1, true, "ext4")),
+			},
+			equivalent: true,
+		},
+		{
+			name: "other size",
+			configured: []interface{}{
+				testReadLayout(1, []interface{}{0, 1}, testPartition("/boot", 1000, false, "ext4"), testPartition("/", 1, true, "ext4")),
+			},
+		},
+		{
+			name: "other fs",
+			configured: []interface{}{
+				testReadLayout(1, []interface{}{0, 1}, testPartition("/boot", 500, false, "xfs"), testPartition("/", 1, true, "ext4")),
+			},
+		},
+		{
+			name: "other raid",
+			configured: []interface{}{
+				testReadLayout(0, []interface{}{0, 1}, testPartition("/boot", 500, false, "ext4"), testPartition("/", 1, true, "ext4")),
+			},
+		},
+		{
+			name: "other partitions",
+			configured: []interface{}{
+				testReadLayout(1, []interface{}{0, 1}, testPartition("/", 1, true, "ext4")),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if equivalent := layoutsEquivalent(current, c.configured); equivalent != c.equivalent {
+				t.Fatalf("expected %t, got %t", c.equivalent, equivalent)
+			}
+		})
+	}
+}
+
+func TestReinstallOperatingSystemID(t *testing.T) {
+	// the imported server has no operating system in the configuration
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":                     "1",
+			"hostname":               "node-1",
+			"operating_system":       "Ubuntu 22.04-server x86_64",
+			"operating_system_id":    "5",
+			"ssh_key_fingerprints.#": "0",
+		},
+		RawConfig: cty.ObjectVal(map[string]cty.Value{
+			"hostname": cty.StringVal("node-1"),
+		}),
+	}
+
+	d := resourceServerscomDedicatedServer().Data(state)
+
+	id, diags := reinstallOperatingSystemID(context.Background(), d, nil)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if id == nil || *id != 5 {
+		t.Fatalf("expected the installed operating system 5 to be reinstalled, got %v", id)
+	}
+
+	delete(state.Attributes, "operating_system_id")
+	d = resourceServerscomDedicatedServer().Data(state)
+
+	if id, _ := reinstallOperatingSystemID(context.Background(), d, nil); id != nil {
+		t.Fatalf("expected no operating system, got %d", *id)
+	}
+}
+
+// testResourceChanges is a plan of the reinstall attributes, keys missing from new are unchanged
+type testResourceChanges struct {
+	old, new map[string]interface{}
+}
+
+func (c testResourceChanges) Get(key string) interface{} {
+	if v, ok := c.new[key]; ok {
+		return v
+	}
+	return c.old[key]
+}
+
+func (c testResourceChanges) GetChange(key string) (interface{}, interface{}) {
+	return c.old[key], c.Get(key)
+}
+
+func (c testResourceChanges) HasChange(key string) bool {
+	_, ok := c.new[key]
+	return ok
+}
+
+func TestDedicatedServerReinstallChanges(t *testing.T) {
+	layout := []interface{}{testReadLayout(0, []interface{}{0}, testPartition("/", 1, true, "ext4"))}
+
+	cases := []struct {
+		name    string
+		old     map[string]interface{}
+		new     map[string]interface{}
+		changed []string
+	}{
+		{
+			name:    "unknown layout is stored",
+			old:     map[string]interface{}{"layout": []interface{}{}, "layout_reported": false},
+			new:     map[string]interface{}{"layout": layout},
+			changed: nil,
+		},
+		{
+			name:    "empty reported layout is changed",
+			old:     map[string]interface{}{"layout": []interface{}{}, "layout_reported": true},
+			new:     map[string]interface{}{"layout": layout},
+			changed: []string{"layout"},
+		},
+		{
+			name:    "stored layout is changed",
+			old:     map[string]interface{}{"layout": layout, "layout_reported": false},
+			new:     map[string]interface{}{"layout": []interface{}{testReadLayout(0, []interface{}{1}, testPartition("/", 1, true, "ext4"))}},
+			changed: []string{"layout"},
+		},
+		{
+			name:    "ssh keys with unknown layout",
+			old:     map[string]interface{}{"layout": []interface{}{}, "layout_reported": false},
+			new:     map[string]interface{}{"layout": layout, "ssh_key_fingerprints": []interface{}{"aa:bb"}},
+			changed: []string{"ssh_key_fingerprints"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			changed := dedicatedServerReinstallChanges(testResourceChanges{old: c.old, new: c.new})
+			if !reflect.DeepEqual(changed, c.changed) {
+				t.Fatalf("expected %v, got %v", c.changed, changed)
+			}
+		})
+	}
+}
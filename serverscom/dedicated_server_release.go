@@ -0,0 +1,77 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+const (
+	releaseModeEndOfBillingPeriod = "end_of_billing_period"
+	releaseModeDate               = "date"
+)
+
+// customizeDiffDedicatedServerRelease checks release_after is set for the date release mode only
+// and plans to abort the release of a server scheduled for release which is still in the configuration
+func customizeDiffDedicatedServerRelease(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.NewValueKnown("release_mode") && d.NewValueKnown("release_after") {
+		releaseMode, releaseAfter := d.Get("release_mode").(string), d.Get("release_after").(string)
+
+		switch {
+		case releaseMode == releaseModeDate && releaseAfter == "":
+			return fmt.Errorf("release_after is required when release_mode is %s", releaseModeDate)
+		case releaseMode != releaseModeDate && releaseAfter != "":
+			return fmt.Errorf("release_after can be set only when release_mode is %s", releaseModeDate)
+		}
+	}
+
+	if d.Id() != "" && d.Get("scheduled_release_at").(string) != "" {
+		return d.SetNew("scheduled_release_at", "")
+	}
+
+	return nil
+}
+
+// expandScheduleReleaseInput returns the input to schedule the release according to release_mode
+func expandScheduleReleaseInput(d *schema.ResourceData) scgo.ScheduleReleaseInput {
+	input := scgo.ScheduleReleaseInput{}
+
+	if d.Get("release_mode").(string) == releaseModeDate {
+		input.ReleaseAfter = d.Get("release_after").(string)
+	}
+
+	return input
+}
+
+// formatScheduledRelease returns scheduled_release_at for the scheduled release time, empty when it isn't scheduled
+func formatScheduledRelease(scheduledRelease *time.Time) string {
+	if scheduledRelease == nil {
+		return ""
+	}
+
+	return formatTime(*scheduledRelease)
+}
+
+// abortDedicatedServerRelease aborts the scheduled release of the dedicated server, if any
+func abortDedicatedServerRelease(ctx context.Context, client *scgo.Client, id string) error {
+	dedicatedServer, err := client.Hosts.GetDedicatedServer(ctx, id)
+	if err != nil {
+		return fmt.Errorf("Error retrieving dedicated server (%s): %s", id, err)
+	}
+
+	if dedicatedServer.ScheduledRelease == nil {
+		return nil
+	}
+
+	log.Printf("[WARN] Aborting release of dedicated server (%s) scheduled at %s", id, formatScheduledRelease(dedicatedServer.ScheduledRelease))
+
+	if _, err := client.Hosts.AbortReleaseForDedicatedServer(ctx, id); err != nil {
+		return fmt.Errorf("Error aborting release of dedicated server (%s): %s", id, describeAPIError(err))
+	}
+
+	return nil
+}
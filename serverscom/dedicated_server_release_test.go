@@ -0,0 +1,39 @@
+package serverscom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestExpandScheduleReleaseInput(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceServerscomDedicatedServer().Schema, map[string]interface{}{
+		"hostname": "node-1",
+	})
+
+	if input := expandScheduleReleaseInput(d); input.ReleaseAfter != "" {
+		t.Fatalf("expected release at the end of the billing period, got %q", input.ReleaseAfter)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceServerscomDedicatedServer().Schema, map[string]interface{}{
+		"hostname":      "node-1",
+		"release_mode":  "date",
+		"release_after": "2030-01-01T00:00:00Z",
+	})
+
+	if input := expandScheduleReleaseInput(d); input.ReleaseAfter != "2030-01-01T00:00:00Z" {
+		t.Fatalf("expected release after the date, got %q", input.ReleaseAfter)
+	}
+}
+
+func TestFormatScheduledRelease(t *testing.T) {
+	if v := formatScheduledRelease(nil); v != "" {
+		t.Fatalf("expected empty value, got %q", v)
+	}
+
+	scheduledRelease := time.Date(2030, 1, 1, 3, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	if v := formatScheduledRelease(&scheduledRelease); v != "2030-01-01T00:00:00Z" {
+		t.Fatalf("unexpected value %q", v)
+	}
+}
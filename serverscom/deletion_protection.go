@@ -0,0 +1,100 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Prevent the resource from being destroyed or replaced until the flag is turned off in a separate apply",
+	}
+}
+
+func deletionProtectionError(name, id string) error {
+	return fmt.Errorf(
+		"%s (%s) has deletion_protection enabled, set deletion_protection to false and apply before destroying or replacing it",
+		name, id,
+	)
+}
+
+// checkDeletionProtection refuses to delete the resource while deletion_protection is set in the state
+func checkDeletionProtection(d *schema.ResourceData, name string) error {
+	if d.Get("deletion_protection").(bool) {
+		return deletionProtectionError(name, d.Id())
+	}
+
+	return nil
+}
+
+// customizeDiffDeletionProtection refuses to plan a replacement of the resource while deletion_protection is set in the state.
+// The prior value is used, so turning the flag off in the same apply doesn't allow the replacement.
+// Only changes of ForceNew attributes are seen here, replacements requested with -replace or taint
+// are refused at apply time by checkDeletionProtection in Delete.
+func customizeDiffDeletionProtection(name string, resource func() *schema.Resource) schema.CustomizeDiffFunc {
+	var (
+		keys []string
+		once sync.Once
+	)
+
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.Id() == "" {
+			return nil
+		}
+
+		if protected, _ := d.GetChange("deletion_protection"); !protected.(bool) {
+			return nil
+		}
+
+		// the schema can't be read while the resource is built, the keys are collected on the first plan
+		once.Do(func() {
+			keys = forceNewKeys(resource().Schema)
+		})
+
+		for _, key := range keys {
+			if d.HasChange(key) {
+				return fmt.Errorf("changing %s requires replacement: %s", key, deletionProtectionError(name, d.Id()))
+			}
+		}
+
+		return nil
+	}
+}
+
+// forceNewKeys returns top level attributes which force a replacement themselves or by any of their nested attributes
+func forceNewKeys(schemaMap map[string]*schema.Schema) []string {
+	var keys []string
+
+	for key, s := range schemaMap {
+		if isForceNew(s) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func isForceNew(s *schema.Schema) bool {
+	if s.ForceNew {
+		return true
+	}
+
+	if elem, ok := s.Elem.(*schema.Resource); ok {
+		for _, nested := range elem.Schema {
+			if isForceNew(nested) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+package serverscom
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestForceNewKeys(t *testing.T) {
+	keys := forceNewKeys(map[string]*schema.Schema{
+		"name":  {Type: schema.TypeString, Optional: true},
+		"image": {Type: schema.TypeString, Required: true, ForceNew: true},
+		"disk": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"size": {Type: schema.TypeInt, Required: true, ForceNew: true},
+				},
+			},
+		},
+	})
+
+	if expected := []string{"disk", "image"}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("expected %v, got %v", expected, keys)
+	}
+}
+
+func TestCheckDeletionProtection(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceServerscomRBSVolume().Schema, map[string]interface{}{
+		"deletion_protection": true,
+	})
+	d.SetId("1")
+
+	if err := checkDeletionProtection(d, "RBS volume"); err == nil {
+		t.Fatal("expected protected volume deletion to be refused")
+	}
+
+	d.Set("deletion_protection", false)
+
+	if err := checkDeletionProtection(d, "RBS volume"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestCustomizeDiffDeletionProtection(t *testing.T) {
+	resource := resourceServerscomRBSVolume()
+
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":                  "1",
+			"name":                "volume",
+			"size":                "10",
+			"location_id":         "1",
+			"flavor_id":           "1",
+			"deletion_protection": "true",
+		},
+	}
+
+	config := func(locationID int, deletionProtection bool) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                "volume",
+			"size":                10,
+			"location_id":         locationID,
+			"flavor_id":           1,
+			"deletion_protection": deletionProtection,
+		})
+	}
+
+	meta := &ProviderMeta{}
+
+	if _, err := resource.Diff(context.Background(), state, config(1, true), meta); err != nil {
+		t.Fatalf("unexpected error without replacement: %s", err)
+	}
+
+	// turning the flag off in the same apply doesn't allow the replacement
+	for _, deletionProtection := range []bool{true, false} {
+		_, err := resource.Diff(context.Background(), state, config(2, deletionProtection), meta)
+		if err == nil || !strings.Contains(err.Error(), "changing location_id requires replacement") {
+			t.Fatalf("expected the replacement to be refused, got %v", err)
+		}
+	}
+
+	state.Attributes["deletion_protection"] = "false"
+
+	diff, err := resource.Diff(context.Background(), state, config(2, false), meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !diff.RequiresNew() {
+		t.Fatalf("expected the location change to replace the unprotected volume")
+	}
+}
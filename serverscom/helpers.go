@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"strings"
+	"time"
 )
 
 func expandIntList(elements []interface{}) []int {
@@ -31,3 +32,35 @@ func normalizeString(str string) string {
 func compareStrings(k, old, new string, d *schema.ResourceData) bool {
 	return normalizeString(old) == normalizeString(new)
 }
+
+// stringValue returns the string the pointer refers to, or an empty string for nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// intValue returns the integer the pointer refers to, or zero for nil
+func intValue(i *int64) int {
+	if i == nil {
+		return 0
+	}
+
+	return int(*i)
+}
+
+// formatTime returns the timestamp reported by the api in RFC3339
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// waiterDelayDivisor shortens delays and poll intervals of the status waiters.
+// Tests against the fake api raise it, the fake changes statuses on every read.
+var waiterDelayDivisor time.Duration = 1
+
+// waiterDelay returns the delay or poll interval of a status waiter
+func waiterDelay(d time.Duration) time.Duration {
+	return d / waiterDelayDivisor
+}
@@ -0,0 +1,69 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	importHostnamePrefix = "hostname="
+	importLabelPrefix    = "label:"
+)
+
+// importByReference returns an importer accepting the id, hostname=<hostname> or label:<label selector>
+func importByReference(name string, lookup candidateLookup, resource func() *schema.Resource) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		if err := setImportDefaults(d, resource().Schema); err != nil {
+			return nil, err
+		}
+
+		reference := d.Id()
+
+		var hostname, labelSelector string
+		switch {
+		case strings.HasPrefix(reference, importHostnamePrefix):
+			hostname = strings.TrimPrefix(reference, importHostnamePrefix)
+		case strings.HasPrefix(reference, importLabelPrefix):
+			labelSelector = strings.TrimPrefix(reference, importLabelPrefix)
+		default:
+			return []*schema.ResourceData{d}, nil
+		}
+
+		if hostname == "" && labelSelector == "" {
+			return nil, fmt.Errorf("invalid import id %q, expected <id>, %s<hostname> or %s<key>=<value>", reference, importHostnamePrefix, importLabelPrefix)
+		}
+
+		candidates, err := lookup(ctx, meta.(*ProviderMeta).Client, hostname, labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("Error looking up %s by %s: %s", name, reference, err)
+		}
+
+		candidate, err := selectCandidate(name, reference, hostname, candidates, "import one of them by id")
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(candidate.ID)
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+// setImportDefaults sets the defaults of top level attributes, the ones Read doesn't set would be null
+// in the imported state otherwise and the next plan would update them to the defaults
+func setImportDefaults(d *schema.ResourceData, schemaMap map[string]*schema.Schema) error {
+	for key, s := range schemaMap {
+		if s.Default == nil {
+			continue
+		}
+
+		if err := d.Set(key, s.Default); err != nil {
+			return fmt.Errorf("Error setting %s: %s", key, err)
+		}
+	}
+
+	return nil
+}
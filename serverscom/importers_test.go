@@ -0,0 +1,31 @@
+package serverscom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestImportByReference_SetsDefaults(t *testing.T) {
+	resource := resourceServerscomDedicatedServer()
+	d := resource.Data(&terraform.InstanceState{ID: "1"})
+
+	states, err := resource.Importer.StateContext(context.Background(), d, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	attributes := states[0].State().Attributes
+	expected := map[string]string{
+		"deletion_protection": "false",
+		"reinstall_on_change": "false",
+		"release_mode":        releaseModeEndOfBillingPeriod,
+	}
+
+	for key, value := range expected {
+		if got, ok := attributes[key]; !ok || got != value {
+			t.Errorf("expected %s to be %q after the import, got %q", key, value, got)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// labelsAllSchema returns schema of the labels_all attribute,
+// which holds resource labels merged with the provider default_labels
+func labelsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Description: "All labels of the resource, including the provider default_labels",
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// mergeLabels merges the provider default labels with the resource labels.
+// Resource labels take precedence over the default ones.
+func mergeLabels(defaults map[string]string, labels map[string]interface{}) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(labels))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v.(string)
+	}
+
+	return merged
+}
+
+// expandLabels returns resource labels merged with the provider default_labels
+func expandLabels(d *schema.ResourceData, meta interface{}) map[string]string {
+	return mergeLabels(meta.(*ProviderMeta).DefaultLabels, d.Get("labels").(map[string]interface{}))
+}
+
+// setLabels sets labels and labels_all from the labels returned by the api.
+// Labels that come only from the provider default_labels are left out of labels,
+// the idempotency label set by the provider is left out of both.
+func setLabels(d *schema.ResourceData, labels map[string]string, meta interface{}) error {
+	defaults := meta.(*ProviderMeta).DefaultLabels
+	configured := d.Get("labels").(map[string]interface{})
+
+	allLabels := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if k != idempotencyLabel {
+			allLabels[k] = v
+		}
+	}
+	labels = allLabels
+
+	ownLabels := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if defaultValue, ok := defaults[k]; ok && defaultValue == v {
+			if _, ok := configured[k]; !ok {
+				continue
+			}
+		}
+		ownLabels[k] = v
+	}
+
+	if err := d.Set("labels", ownLabels); err != nil {
+		return fmt.Errorf("Unable to set `labels` attribute: %s", err)
+	}
+
+	if err := d.Set("labels_all", labels); err != nil {
+		return fmt.Errorf("Unable to set `labels_all` attribute: %s", err)
+	}
+
+	return nil
+}
+
+// customizeDiffLabelsAll keeps labels_all in the plan in sync with labels and the provider default_labels
+func customizeDiffLabelsAll(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("labels") {
+		return d.SetNewComputed("labels_all")
+	}
+
+	merged := mergeLabels(meta.(*ProviderMeta).DefaultLabels, d.Get("labels").(map[string]interface{}))
+
+	current := make(map[string]string)
+	for k, v := range d.Get("labels_all").(map[string]interface{}) {
+		current[k] = v.(string)
+	}
+
+	if reflect.DeepEqual(current, merged) {
+		return nil
+	}
+
+	return d.SetNew("labels_all", merged)
+}
@@ -0,0 +1,44 @@
+package serverscom
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestMergeLabels(t *testing.T) {
+	defaults := map[string]string{"team": "infra", "env": "prod"}
+	labels := map[string]interface{}{"env": "staging", "role": "db"}
+
+	expected := map[string]string{"team": "infra", "env": "staging", "role": "db"}
+
+	if merged := mergeLabels(defaults, labels); !reflect.DeepEqual(merged, expected) {
+		t.Fatalf("expected %v, got %v", expected, merged)
+	}
+}
+
+func TestSetLabels(t *testing.T) {
+	meta := &ProviderMeta{
+		DefaultLabels: map[string]string{"team": "infra", "env": "prod"},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceServerscomSSHKey().Schema, map[string]interface{}{
+		"labels": map[string]interface{}{"env": "prod", "role": "db"},
+	})
+
+	remote := map[string]string{"team": "infra", "env": "prod", "role": "db"}
+	if err := setLabels(d, remote, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedLabels := map[string]interface{}{"env": "prod", "role": "db"}
+	if labels := d.Get("labels"); !reflect.DeepEqual(labels, expectedLabels) {
+		t.Fatalf("expected labels %v, got %v", expectedLabels, labels)
+	}
+
+	expectedLabelsAll := map[string]interface{}{"team": "infra", "env": "prod", "role": "db"}
+	if labelsAll := d.Get("labels_all"); !reflect.DeepEqual(labelsAll, expectedLabelsAll) {
+		t.Fatalf("expected labels_all %v, got %v", expectedLabelsAll, labelsAll)
+	}
+}
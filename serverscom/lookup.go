@@ -0,0 +1,192 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+// lookupCandidate is an object matching the lookup by name or labels
+type lookupCandidate struct {
+	ID   string
+	Name string
+}
+
+// candidateLookup lists objects by the name search pattern or the label selector, one of them is empty
+type candidateLookup func(ctx context.Context, client *scgo.Client, searchPattern, labelSelector string) ([]lookupCandidate, error)
+
+// selectCandidate returns the only candidate matching the reference,
+// the search pattern matches names partially, so candidates are filtered by the exact name.
+// The hint is added to the error when several candidates match.
+func selectCandidate(kind, reference, name string, candidates []lookupCandidate, hint string) (*lookupCandidate, error) {
+	var matches []lookupCandidate
+	for _, candidate := range candidates {
+		if name == "" || candidate.Name == name {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no %s matches %s", kind, reference)
+	case 1:
+		return &matches[0], nil
+	}
+
+	var found []string
+	for _, match := range matches {
+		found = append(found, fmt.Sprintf("%s (%s)", match.Name, match.ID))
+	}
+	sort.Strings(found)
+
+	return nil, fmt.Errorf("%s matches %d %ss: %s, %s", reference, len(matches), kind, strings.Join(found, ", "), hint)
+}
+
+// withLookupFields makes the id of the data source optional, so exactly one of the id,
+// the name attribute or label_selector (unless it's empty) is used to find the object
+func withLookupFields(schemaMap map[string]*schema.Schema, nameAttribute, labelSelector string) map[string]*schema.Schema {
+	lookupFields := []string{"id", nameAttribute}
+
+	if labelSelector != "" {
+		lookupFields = append(lookupFields, labelSelector)
+
+		schemaMap[labelSelector] = &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		}
+	}
+
+	for _, key := range lookupFields {
+		schemaMap[key].Optional = true
+		schemaMap[key].ExactlyOneOf = lookupFields
+	}
+
+	return schemaMap
+}
+
+// lookupDataSourceID returns the id of the data source object, either set by the id
+// or looked up by the name attribute or label_selector
+func lookupDataSourceID(ctx context.Context, d *schema.ResourceData, meta interface{}, kind, nameAttribute string, lookup candidateLookup) (string, error) {
+	if id, ok := d.GetOk("id"); ok {
+		return id.(string), nil
+	}
+
+	name := d.Get(nameAttribute).(string)
+	labelSelector := d.Get("label_selector").(string)
+
+	reference := fmt.Sprintf("%s %q", nameAttribute, name)
+	if labelSelector != "" {
+		reference = fmt.Sprintf("label_selector %q", labelSelector)
+	}
+
+	candidates, err := lookup(ctx, meta.(*ProviderMeta).Client, name, labelSelector)
+	if err != nil {
+		return "", fmt.Errorf("Error looking up %s by %s: %s", kind, reference, err)
+	}
+
+	candidate, err := selectCandidate(kind, reference, name, candidates, "set the id or narrow the lookup down")
+	if err != nil {
+		return "", err
+	}
+
+	return candidate.ID, nil
+}
+
+// hostLookup lists hosts of the type, released hosts are skipped
+func hostLookup(hostType string) candidateLookup {
+	return func(ctx context.Context, client *scgo.Client, searchPattern, labelSelector string) ([]lookupCandidate, error) {
+		collection := client.Hosts.Collection().SetParam("type", hostType)
+		if searchPattern != "" {
+			collection = collection.SetParam("search_pattern", searchPattern)
+		}
+		if labelSelector != "" {
+			collection = collection.SetParam("label_selector", labelSelector)
+		}
+
+		hosts, err := collection.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var candidates []lookupCandidate
+		for _, host := range hosts {
+			if host.Type != hostType || host.Status == "released" {
+				continue
+			}
+
+			candidates = append(candidates, lookupCandidate{ID: host.ID, Name: host.Title})
+		}
+
+		return candidates, nil
+	}
+}
+
+func cloudComputingInstanceLookup(ctx context.Context, client *scgo.Client, searchPattern, labelSelector string) ([]lookupCandidate, error) {
+	collection := client.CloudComputingInstances.Collection()
+	if searchPattern != "" {
+		collection = collection.SetParam("search_pattern", searchPattern)
+	}
+	if labelSelector != "" {
+		collection = collection.SetParam("label_selector", labelSelector)
+	}
+
+	instances, err := collection.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []lookupCandidate
+	for _, instance := range instances {
+		candidates = append(candidates, lookupCandidate{ID: instance.ID, Name: instance.Name})
+	}
+
+	return candidates, nil
+}
+
+func l2SegmentLookup(ctx context.Context, client *scgo.Client, searchPattern, labelSelector string) ([]lookupCandidate, error) {
+	collection := client.L2Segments.Collection()
+	if searchPattern != "" {
+		collection = collection.SetParam("search_pattern", searchPattern)
+	}
+	if labelSelector != "" {
+		collection = collection.SetParam("label_selector", labelSelector)
+	}
+
+	segments, err := collection.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []lookupCandidate
+	for _, segment := range segments {
+		candidates = append(candidates, lookupCandidate{ID: segment.ID, Name: segment.Name})
+	}
+
+	return candidates, nil
+}
+
+func rbsVolumeLookup(ctx context.Context, client *scgo.Client, searchPattern, labelSelector string) ([]lookupCandidate, error) {
+	collection := client.RemoteBlockStorageVolumes.Collection()
+	if searchPattern != "" {
+		collection = collection.SetParam("search_pattern", searchPattern)
+	}
+	if labelSelector != "" {
+		collection = collection.SetParam("label_selector", labelSelector)
+	}
+
+	volumes, err := collection.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []lookupCandidate
+	for _, volume := range volumes {
+		candidates = append(candidates, lookupCandidate{ID: volume.ID, Name: volume.Name})
+	}
+
+	return candidates, nil
+}
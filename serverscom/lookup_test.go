@@ -0,0 +1,68 @@
+package serverscom
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func TestSelectCandidate(t *testing.T) {
+	candidates := []lookupCandidate{
+		{ID: "1", Name: "web-01"},
+		{ID: "2", Name: "web-010"},
+		{ID: "3", Name: "db-01"},
+	}
+
+	candidate, err := selectCandidate("dedicated server", "hostname=web-01", "web-01", candidates, "import one of them by id")
+	if err != nil || candidate.ID != "1" {
+		t.Fatalf("expected exact hostname match, got %v, %v", candidate, err)
+	}
+
+	_, err = selectCandidate("dedicated server", "hostname=web-02", "web-02", candidates, "import one of them by id")
+	if err == nil || err.Error() != "no dedicated server matches hostname=web-02" {
+		t.Fatalf("expected no match error, got %v", err)
+	}
+
+	_, err = selectCandidate("dedicated server", "label:role=web", "", candidates[:2], "import one of them by id")
+	if err == nil || !strings.Contains(err.Error(), "label:role=web matches 2 dedicated servers: web-01 (1), web-010 (2), import one of them by id") {
+		t.Fatalf("expected ambiguous match error, got %v", err)
+	}
+}
+
+func TestWithLookupFields(t *testing.T) {
+	resource := dataSourceServerscomDedicatedServer()
+	if err := resource.InternalValidate(nil, false); err != nil {
+		t.Fatalf("unexpected schema error: %s", err)
+	}
+
+	expected := []string{"id", "title", "label_selector"}
+	for _, key := range expected {
+		s := resource.Schema[key]
+		if !s.Optional || s.Required || !reflect.DeepEqual(s.ExactlyOneOf, expected) {
+			t.Errorf("expected %s to be an optional lookup field, got %#v", key, s)
+		}
+	}
+
+	location := dataSourceServerscomLocation().Schema
+	if _, ok := location["label_selector"]; ok {
+		t.Errorf("expected no label_selector for locations")
+	}
+	if !reflect.DeepEqual(location["code"].ExactlyOneOf, []string{"id", "code"}) {
+		t.Errorf("unexpected location lookup fields: %v", location["code"].ExactlyOneOf)
+	}
+}
+
+func TestFindLocationByCode(t *testing.T) {
+	locations := []scgo.Location{{ID: 1, Code: "AMS1"}, {ID: 2, Code: "SJC1"}}
+
+	location, err := findLocationByCode(locations, "ams1")
+	if err != nil || location.ID != 1 {
+		t.Fatalf("expected AMS1 location, got %v, %v", location, err)
+	}
+
+	if _, err := findLocationByCode(locations, "DFW1"); err == nil || err.Error() != "no location found with code DFW1" {
+		t.Fatalf("expected no location error, got %v", err)
+	}
+}
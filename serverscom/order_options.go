@@ -0,0 +1,310 @@
+package serverscom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+// resourceGetter is the part of schema.ResourceData and schema.ResourceDiff used to read order options
+type resourceGetter interface {
+	Get(key string) interface{}
+	GetOk(key string) (interface{}, bool)
+	GetRawConfig() cty.Value
+}
+
+// resourceDiffer is the part of schema.ResourceDiff used by the order options validation
+type resourceDiffer interface {
+	resourceGetter
+
+	Id() string
+	HasChanges(keys ...string) bool
+	NewValueKnown(key string) bool
+}
+
+// optionRef references an order option either by its ID or by its name
+type optionRef struct {
+	ID   string
+	Name string
+
+	// Path points at the attribute holding the reference
+	Path cty.Path
+}
+
+// getOptionRef returns reference to the order option configured by the name or by the ID attribute
+func getOptionRef(d resourceGetter, nameKey, idKey string) optionRef {
+	if isConfigured(d, cty.GetAttrPath(idKey)) {
+		return optionRef{ID: formatOptionID(d.Get(idKey)), Path: cty.GetAttrPath(idKey)}
+	}
+
+	if isConfigured(d, cty.GetAttrPath(nameKey)) {
+		return optionRef{Name: d.Get(nameKey).(string), Path: cty.GetAttrPath(nameKey)}
+	}
+
+	return optionRef{Path: cty.GetAttrPath(nameKey)}
+}
+
+// getSlotDriveModelRef returns reference to the drive model of the slot with the index
+func getSlotDriveModelRef(d resourceGetter, index int, slot map[string]interface{}) optionRef {
+	path := cty.GetAttrPath("slot").IndexInt(index)
+
+	if isConfigured(d, path.GetAttr("drive_model_id")) {
+		return optionRef{ID: formatOptionID(slot["drive_model_id"]), Path: path.GetAttr("drive_model_id")}
+	}
+
+	if isConfigured(d, path.GetAttr("drive_model")) {
+		return optionRef{Name: slot["drive_model"].(string), Path: path.GetAttr("drive_model")}
+	}
+
+	return optionRef{Path: path.GetAttr("drive_model")}
+}
+
+// isConfigured reports whether the attribute is set in the configuration.
+// Values of computed attributes come from the state, so they can't be told apart by Get.
+func isConfigured(d resourceGetter, path cty.Path) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		_, ok := d.GetOk(formatAttributePath(path))
+		return ok
+	}
+
+	value, err := path.Apply(config)
+
+	return err == nil && !value.IsNull()
+}
+
+func formatOptionID(id interface{}) string {
+	switch v := id.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case string:
+		return v
+	}
+
+	return ""
+}
+
+// IsEmpty reports whether neither ID nor name is set
+func (r optionRef) IsEmpty() bool {
+	return r.ID == "" && r.Name == ""
+}
+
+func (r optionRef) String() string {
+	if r.ID != "" {
+		return fmt.Sprintf("ID %s", r.ID)
+	}
+
+	return r.Name
+}
+
+func (r optionRef) matches(id, name string) bool {
+	if r.ID != "" {
+		return r.ID == id
+	}
+
+	return normalizeString(r.Name) == normalizeString(name)
+}
+
+// label returns the value listed among valid values, IDs are listed for references by ID
+func (r optionRef) label(id, name string) string {
+	if r.ID != "" {
+		return id
+	}
+
+	return name
+}
+
+func (r optionRef) notFound(kind string, valid []string) error {
+	return newOptionNotFoundError(kind, r.String(), valid)
+}
+
+var (
+	dedicatedServerOrderOptions = []string{
+		"location", "location_id", "server_model", "server_model_id", "operating_system", "operating_system_id",
+		"public_uplink", "public_uplink_id", "private_uplink", "private_uplink_id", "bandwidth", "bandwidth_id", "slot",
+	}
+	sbmServerOrderOptions     = []string{"location", "location_id", "flavor", "flavor_id", "operating_system", "operating_system_id"}
+	cloudInstanceOrderOptions = []string{"region", "region_id", "flavor", "flavor_id", "image", "image_id"}
+)
+
+// customizeDiffDedicatedServerOrderOptions resolves order options of the dedicated server during plan
+func customizeDiffDedicatedServerOrderOptions(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	return validateDedicatedServerOrderOptions(ctx, meta.(*ProviderMeta).Cache, d)
+}
+
+// customizeDiffSBMServerOrderOptions resolves order options of the SBM server during plan
+func customizeDiffSBMServerOrderOptions(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	return validateSBMServerOrderOptions(ctx, meta.(*ProviderMeta).Cache, d)
+}
+
+// customizeDiffCloudInstanceOrderOptions resolves region, flavor and image of the cloud instance during plan
+func customizeDiffCloudInstanceOrderOptions(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// the flavor is upgraded in place, so the other form of it is known after apply only
+	if d.Id() != "" && d.HasChanges("flavor", "flavor_id") {
+		computedKey := "flavor_id"
+		if isConfigured(d, cty.GetAttrPath("flavor_id")) {
+			computedKey = "flavor"
+		}
+
+		if err := d.SetNewComputed(computedKey); err != nil {
+			return err
+		}
+	}
+
+	return validateCloudInstanceOrderOptions(ctx, meta.(*ProviderMeta).Cache, d)
+}
+
+// refKnown reports whether the value of the referencing attribute is known during plan
+func refKnown(d resourceDiffer, ref optionRef) bool {
+	return d.NewValueKnown(formatAttributePath(ref.Path))
+}
+
+func validateDedicatedServerOrderOptions(ctx context.Context, cache *Cache, d resourceDiffer) error {
+	if d.Id() != "" && !d.HasChanges(dedicatedServerOrderOptions...) {
+		return nil
+	}
+
+	locationRef := getOptionRef(d, "location", "location_id")
+	serverModelRef := getOptionRef(d, "server_model", "server_model_id")
+
+	// the rest of the options depend on location and server model,
+	// they are validated on apply when those are known after apply only
+	if !refKnown(d, locationRef) || !refKnown(d, serverModelRef) {
+		return nil
+	}
+
+	location, err := getLocation(ctx, cache, locationRef)
+	if err != nil {
+		return optionDiffError(err, locationRef.Path)
+	}
+
+	serverModel, err := getServerModel(ctx, cache, location.ID, serverModelRef)
+	if err != nil {
+		return optionDiffError(err, serverModelRef.Path)
+	}
+
+	var errs []error
+
+	if operatingSystemRef := getOptionRef(d, "operating_system", "operating_system_id"); !operatingSystemRef.IsEmpty() && refKnown(d, operatingSystemRef) {
+		if _, err := getOperatingSystem(ctx, cache, location.ID, serverModel.ID, operatingSystemRef); err != nil {
+			errs = append(errs, optionDiffError(err, operatingSystemRef.Path))
+		}
+	}
+
+	if privateUplinkRef := getOptionRef(d, "private_uplink", "private_uplink_id"); !privateUplinkRef.IsEmpty() && refKnown(d, privateUplinkRef) {
+		if _, err := getUplink(ctx, cache, location.ID, serverModel.ID, privateUplinkRef); err != nil {
+			errs = append(errs, optionDiffError(err, privateUplinkRef.Path))
+		}
+	}
+
+	publicUplinkRef := getOptionRef(d, "public_uplink", "public_uplink_id")
+	bandwidthRef := getOptionRef(d, "bandwidth", "bandwidth_id")
+
+	if refKnown(d, publicUplinkRef) && refKnown(d, bandwidthRef) {
+		var publicUplink *scgo.UplinkOption
+		if !publicUplinkRef.IsEmpty() {
+			publicUplink, err = getUplink(ctx, cache, location.ID, serverModel.ID, publicUplinkRef)
+			if err != nil {
+				errs = append(errs, optionDiffError(err, publicUplinkRef.Path))
+			}
+		}
+
+		switch {
+		case !bandwidthRef.IsEmpty() && publicUplinkRef.IsEmpty():
+			errs = append(errs, fmt.Errorf("%s: public_uplink must be specified, when bandwidth is present", formatAttributePath(bandwidthRef.Path)))
+		case bandwidthRef.IsEmpty() && !publicUplinkRef.IsEmpty():
+			errs = append(errs, fmt.Errorf("bandwidth: bandwidth must be specified, when public uplink is present"))
+		case !bandwidthRef.IsEmpty() && publicUplink != nil:
+			if _, err := getBandwidth(ctx, cache, location.ID, serverModel.ID, publicUplink.ID, bandwidthRef); err != nil {
+				errs = append(errs, optionDiffError(err, bandwidthRef.Path))
+			}
+		}
+	}
+
+	for i, slotSchema := range d.Get("slot").([]interface{}) {
+		path := cty.GetAttrPath("slot").IndexInt(i)
+		if isConfigured(d, path.GetAttr("drive_model")) && isConfigured(d, path.GetAttr("drive_model_id")) {
+			errs = append(errs, fmt.Errorf("slot.%d: only one of drive_model, drive_model_id can be specified", i))
+			continue
+		}
+
+		driveModelRef := getSlotDriveModelRef(d, i, slotSchema.(map[string]interface{}))
+		if driveModelRef.IsEmpty() || !refKnown(d, driveModelRef) {
+			continue
+		}
+
+		if _, err := getDriveModel(ctx, cache, location.ID, serverModel.ID, driveModelRef); err != nil {
+			errs = append(errs, optionDiffError(err, driveModelRef.Path))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateSBMServerOrderOptions(ctx context.Context, cache *Cache, d resourceDiffer) error {
+	if d.Id() != "" && !d.HasChanges(sbmServerOrderOptions...) {
+		return nil
+	}
+
+	locationRef := getOptionRef(d, "location", "location_id")
+	flavorRef := getOptionRef(d, "flavor", "flavor_id")
+
+	if !refKnown(d, locationRef) || !refKnown(d, flavorRef) {
+		return nil
+	}
+
+	location, err := getLocation(ctx, cache, locationRef)
+	if err != nil {
+		return optionDiffError(err, locationRef.Path)
+	}
+
+	flavor, err := getSBMFlavor(ctx, cache, location.ID, flavorRef)
+	if err != nil {
+		return optionDiffError(err, flavorRef.Path)
+	}
+
+	if operatingSystemRef := getOptionRef(d, "operating_system", "operating_system_id"); !operatingSystemRef.IsEmpty() && refKnown(d, operatingSystemRef) {
+		if _, err := getSBMOperatingSystem(ctx, cache, location.ID, flavor.ID, operatingSystemRef); err != nil {
+			return optionDiffError(err, operatingSystemRef.Path)
+		}
+	}
+
+	return nil
+}
+
+func validateCloudInstanceOrderOptions(ctx context.Context, cache *Cache, d resourceDiffer) error {
+	if d.Id() != "" && !d.HasChanges(cloudInstanceOrderOptions...) {
+		return nil
+	}
+
+	regionRef := getOptionRef(d, "region", "region_id")
+	if !refKnown(d, regionRef) {
+		return nil
+	}
+
+	region, err := getRegion(ctx, cache, regionRef)
+	if err != nil {
+		return optionDiffError(err, regionRef.Path)
+	}
+
+	var errs []error
+
+	if flavorRef := getOptionRef(d, "flavor", "flavor_id"); refKnown(d, flavorRef) {
+		if _, err := getFlavor(ctx, cache, region.ID, flavorRef); err != nil {
+			errs = append(errs, optionDiffError(err, flavorRef.Path))
+		}
+	}
+
+	if imageRef := getOptionRef(d, "image", "image_id"); refKnown(d, imageRef) {
+		if _, err := getImage(ctx, cache, region.ID, imageRef); err != nil {
+			errs = append(errs, optionDiffError(err, imageRef.Path))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,219 @@
+package serverscom
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+// testResourceDiff implements resourceDiffer for a resource being created
+type testResourceDiff struct {
+	values  map[string]interface{}
+	unknown map[string]bool
+}
+
+func (d *testResourceDiff) Id() string { return "" }
+
+func (d *testResourceDiff) Get(key string) interface{} {
+	if v, ok := d.lookup(key); ok {
+		return v
+	}
+	if key == "slot" {
+		return []interface{}{}
+	}
+	return ""
+}
+
+func (d *testResourceDiff) GetOk(key string) (interface{}, bool) {
+	v, ok := d.lookup(key)
+	return v, ok && v != "" && v != 0
+}
+
+// lookup walks flatmap keys like slot.1.drive_model
+func (d *testResourceDiff) lookup(key string) (interface{}, bool) {
+	var value interface{} = d.values
+
+	for _, step := range strings.Split(key, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[step]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			index, err := strconv.Atoi(step)
+			if err != nil || index >= len(v) {
+				return nil, false
+			}
+			value = v[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+func (d *testResourceDiff) GetRawConfig() cty.Value { return cty.NilVal }
+
+func (d *testResourceDiff) HasChanges(keys ...string) bool { return true }
+
+func (d *testResourceDiff) NewValueKnown(key string) bool { return !d.unknown[key] }
+
+func testOrderOptionsCache() *Cache {
+	cache := NewCache(nil, DefaultCacheConfig())
+
+	cache.add("locations", []scgo.Location{{ID: 1, Code: "SJC1"}, {ID: 2, Code: "AMS1"}})
+	cache.add("locations/1/server_models", []scgo.ServerModelOption{{ID: 1, Name: "Dell R440"}})
+	cache.add("locations/1/server_models/1/operating_systems", []scgo.OperatingSystemOption{{ID: 1, Name: "Ubuntu", Version: "18.04-server", Arch: "x86_64"}})
+	cache.add("locations/1/server_models/1/uplinks", []scgo.UplinkOption{{ID: 1, Name: "Public 10 Gbps"}, {ID: 2, Name: "Private 10 Gbps"}})
+	cache.add("locations/1/server_models/1/uplinks/1/bandwidth", []scgo.BandwidthOption{{ID: 1, Name: "19.1 TB"}})
+	cache.add("locations/1/server_models/1/drive_models", []scgo.DriveModel{{ID: 1, Name: "480 GB SSD SATA"}})
+	cache.add("cloud_computing/regions", []scgo.CloudComputingRegion{{ID: 1, Code: "SJC1"}})
+	cache.add("cloud_computing/regions/1/flavors", []scgo.CloudComputingFlavor{{ID: "flv1", Name: "SSD.30"}})
+	cache.add("cloud_computing/regions/1/images", []scgo.CloudComputingImage{{ID: "img1", Name: "Ubuntu 18.04-server x86_64"}})
+
+	return cache
+}
+
+func testDedicatedServerDiff() *testResourceDiff {
+	return &testResourceDiff{
+		values: map[string]interface{}{
+			"location":         "SJC1",
+			"server_model":     "Dell R440",
+			"operating_system": "Ubuntu 18.04-server x86_64",
+			"public_uplink":    "Public 10 Gbps",
+			"private_uplink":   "Private 10 Gbps",
+			"bandwidth":        "19.1 TB",
+			"slot":             []interface{}{map[string]interface{}{"position": 0, "drive_model": "480 GB SSD SATA"}},
+		},
+		unknown: map[string]bool{},
+	}
+}
+
+func TestValidateDedicatedServerOrderOptions(t *testing.T) {
+	cache := testOrderOptionsCache()
+
+	cases := []struct {
+		name   string
+		modify func(d *testResourceDiff)
+		err    string
+	}{
+		{
+			name:   "valid",
+			modify: func(d *testResourceDiff) {},
+		},
+		{
+			name:   "unknown location",
+			modify: func(d *testResourceDiff) { d.values["location"] = "SJC2" },
+			err:    `location: Can't find location by: SJC2. Did you mean "SJC1"?`,
+		},
+		{
+			name:   "location known after apply",
+			modify: func(d *testResourceDiff) { d.values["location"] = "SJC2"; d.unknown["location"] = true },
+		},
+		{
+			name:   "unknown operating system",
+			modify: func(d *testResourceDiff) { d.values["operating_system"] = "Ubuntu 20.04-server x86_64" },
+			err:    "operating_system: Can't find operating system by: Ubuntu 20.04-server x86_64",
+		},
+		{
+			name:   "unknown bandwidth",
+			modify: func(d *testResourceDiff) { d.values["bandwidth"] = "20 TB" },
+			err:    "bandwidth: Can't find bandwidth by: 20 TB",
+		},
+		{
+			name:   "bandwidth without public uplink",
+			modify: func(d *testResourceDiff) { delete(d.values, "public_uplink") },
+			err:    "bandwidth: public_uplink must be specified, when bandwidth is present",
+		},
+		{
+			name:   "public uplink without bandwidth",
+			modify: func(d *testResourceDiff) { delete(d.values, "bandwidth") },
+			err:    "bandwidth: bandwidth must be specified, when public uplink is present",
+		},
+		{
+			name: "unknown drive model",
+			modify: func(d *testResourceDiff) {
+				d.values["slot"] = []interface{}{
+					map[string]interface{}{"position": 0, "drive_model": "480 GB SSD SATA"},
+					map[string]interface{}{"position": 1, "drive_model": "480 GB SSD"},
+				}
+			},
+			err: "slot.1.drive_model: Can't find drive model by: 480 GB SSD",
+		},
+		{
+			name: "options by ID",
+			modify: func(d *testResourceDiff) {
+				delete(d.values, "location")
+				delete(d.values, "operating_system")
+				d.values["location_id"] = 1
+				d.values["operating_system_id"] = 1
+				d.values["slot"] = []interface{}{map[string]interface{}{"position": 0, "drive_model": "", "drive_model_id": 1}}
+			},
+		},
+		{
+			name: "unknown server model ID",
+			modify: func(d *testResourceDiff) {
+				delete(d.values, "server_model")
+				d.values["server_model_id"] = 42
+			},
+			err: "server_model_id: Can't find server model by: ID 42",
+		},
+		{
+			name: "drive model by name and ID",
+			modify: func(d *testResourceDiff) {
+				d.values["slot"] = []interface{}{map[string]interface{}{"position": 0, "drive_model": "480 GB SSD SATA", "drive_model_id": 1}}
+			},
+			err: "slot.0: only one of drive_model, drive_model_id can be specified",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := testDedicatedServerDiff()
+			c.modify(d)
+
+			err := validateDedicatedServerOrderOptions(context.Background(), cache, d)
+
+			if c.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("expected error %q, got %v", c.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateCloudInstanceOrderOptions(t *testing.T) {
+	cache := testOrderOptionsCache()
+
+	d := &testResourceDiff{
+		values: map[string]interface{}{
+			"region": "SJC1",
+			"flavor": "SSD.35",
+			"image":  "Ubuntu 18.04-server x86_64",
+		},
+		unknown: map[string]bool{},
+	}
+
+	err := validateCloudInstanceOrderOptions(context.Background(), cache, d)
+	if err == nil || !strings.Contains(err.Error(), `flavor: Can't find cloud computing flavor by: SSD.35. Did you mean "SSD.30"?`) {
+		t.Fatalf("expected flavor error, got %v", err)
+	}
+
+	d.values["flavor"] = "SSD.30"
+	if err := validateCloudInstanceOrderOptions(context.Background(), cache, d); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,151 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+// idempotencyLabel marks servers ordered by the provider with the key of the create request.
+// It lets the next apply find servers ordered by a create that was interrupted before they got into the state.
+const idempotencyLabel = "terraform.servers.com/idempotency-key"
+
+// hostTypes maps collector resource types to the api host types
+var hostTypes = map[string]string{
+	"dedicated": "dedicated_server",
+	"sbm":       "sbm_server",
+}
+
+// idempotencyKey derives the key of the create request from the hostname and the order configuration.
+// Resource addresses aren't available to the provider, the hostname identifies the server instead.
+func idempotencyKey(hostname string, input ServerCreateInput) (string, error) {
+	checksum, err := calculateServerChecksum(input)
+	if err != nil {
+		return "", err
+	}
+
+	return HashString(hostname + "/" + checksum), nil
+}
+
+// orderServer orders the single host server of the input through the server collector and returns its id.
+// The server left by an interrupted create with the same idempotency key is adopted instead of ordering a new one.
+func orderServer(ctx context.Context, meta interface{}, resourceType string, input ServerCreateInput) (string, error) {
+	hosts := input.GetHosts()
+	if len(hosts) != 1 {
+		return "", fmt.Errorf("expected exactly one host, got %d", len(hosts))
+	}
+
+	hostname, err := getHostHostname(hosts[0])
+	if err != nil {
+		return "", err
+	}
+
+	key, err := idempotencyKey(hostname, input)
+	if err != nil {
+		return "", err
+	}
+
+	hosts[0] = setHostLabel(hosts[0], idempotencyLabel, key)
+	input.SetHosts(hosts)
+
+	orphaned, err := meta.(*ProviderMeta).Client.Hosts.Collection().
+		SetParam("type", hostTypes[resourceType]).
+		SetParam("label_selector", fmt.Sprintf("%s=%s", idempotencyLabel, key)).
+		Collect(ctx)
+	if err != nil {
+		return "", fmt.Errorf("Error looking up servers ordered by an interrupted create: %s", err)
+	}
+
+	if host := findOrphanedHost(orphaned, hostTypes[resourceType], hostname, key); host != nil {
+		log.Printf("[WARN] Adopting server (%s) with hostname '%s' ordered by an interrupted create", host.ID, hostname)
+		return host.ID, nil
+	}
+
+	resultChan, err := meta.(*ProviderMeta).ServerCollector.AddRequest(ctx, resourceType, input)
+	if err != nil {
+		return "", err
+	}
+
+	// waiting for result from collector, the cancelled request is dropped from the batch
+	var result Result
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case result = <-resultChan:
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+
+	if result.Servers.Count() == 0 {
+		return "", fmt.Errorf("Invalid servers count returned by api")
+	}
+
+	// find corresponding server by title matching hostname
+	id := result.Servers.GetIdByHostname(hostname)
+	if id == "" {
+		return "", fmt.Errorf("Can't find the server with title '%s' in api response", hostname)
+	}
+
+	return id, nil
+}
+
+// keepIdempotencyLabel adds the idempotency label of the server to the labels of an update.
+// The label is left out of the state, so the update would remove it otherwise.
+func keepIdempotencyLabel(labels, current map[string]string) map[string]string {
+	if key, ok := current[idempotencyLabel]; ok {
+		labels[idempotencyLabel] = key
+	}
+
+	return labels
+}
+
+// findOrphanedHost returns the host with the hostname and idempotency key which is still being provisioned.
+// The key doesn't identify the resource, so active hosts may be managed by another resource or by the instance
+// being replaced, they are never adopted. Servers scheduled for release aren't adopted either,
+// their release is scheduled by the destroy or replacement of the resource.
+func findOrphanedHost(hosts []scgo.Host, hostType, hostname, key string) *scgo.Host {
+	for i, host := range hosts {
+		if host.Type != hostType || host.Title != hostname || host.Labels[idempotencyLabel] != key {
+			continue
+		}
+
+		if host.Status != "init" && host.Status != "pending" {
+			continue
+		}
+
+		if host.ScheduledRelease != nil {
+			continue
+		}
+
+		return &hosts[i]
+	}
+
+	return nil
+}
+
+// setHostLabel returns the host input with the label added
+func setHostLabel(hostInput interface{}, key, value string) interface{} {
+	withLabel := func(labels map[string]string) map[string]string {
+		result := make(map[string]string, len(labels)+1)
+		for k, v := range labels {
+			result[k] = v
+		}
+		result[key] = value
+
+		return result
+	}
+
+	switch v := hostInput.(type) {
+	case scgo.DedicatedServerHostInput:
+		v.Labels = withLabel(v.Labels)
+		return v
+	case scgo.SBMServerHostInput:
+		v.Labels = withLabel(v.Labels)
+		return v
+	default:
+		return hostInput
+	}
+}
@@ -0,0 +1,173 @@
+package serverscom
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	input := func(hostname string, flavorID int64) *SBMServerCreateInput {
+		v := &SBMServerCreateInput{}
+		v.FlavorModelID = flavorID
+		v.Hosts = []scgo.SBMServerHostInput{{Hostname: hostname}}
+		return v
+	}
+
+	key, err := idempotencyKey("node-1", input("node-1", 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if again, _ := idempotencyKey("node-1", input("node-1", 1)); again != key {
+		t.Fatalf("expected the same key for the same request, got %s and %s", key, again)
+	}
+	if other, _ := idempotencyKey("node-2", input("node-2", 1)); other == key {
+		t.Fatal("expected different keys for different hostnames")
+	}
+	if other, _ := idempotencyKey("node-1", input("node-1", 2)); other == key {
+		t.Fatal("expected different keys for different order configurations")
+	}
+	if len(key) > 63 {
+		t.Fatalf("expected the key to fit in a label value, got %d characters", len(key))
+	}
+}
+
+func TestFindOrphanedHost(t *testing.T) {
+	released := time.Now()
+	labels := map[string]string{idempotencyLabel: "key"}
+
+	hosts := []scgo.Host{
+		{ID: "1", Type: "sbm_server", Title: "node-1", Labels: labels, ScheduledRelease: &released},
+		{ID: "2", Type: "sbm_server", Title: "node-2", Labels: labels},
+		{ID: "3", Type: "dedicated_server", Title: "node-1", Labels: labels},
+		{ID: "4", Type: "sbm_server", Title: "node-1", Labels: map[string]string{idempotencyLabel: "other"}},
+		{ID: "5", Type: "sbm_server", Title: "node-1", Labels: labels, Status: "released"},
+		{ID: "6", Type: "sbm_server", Title: "node-1", Labels: labels, Status: "active"},
+	}
+
+	if host := findOrphanedHost(hosts, "sbm_server", "node-1", "key"); host != nil {
+		t.Fatalf("expected no host to adopt, got %s", host.ID)
+	}
+
+	hosts = append(hosts, scgo.Host{ID: "7", Type: "sbm_server", Title: "node-1", Labels: labels, Status: "pending"})
+
+	if host := findOrphanedHost(hosts, "sbm_server", "node-1", "key"); host == nil || host.ID != "7" {
+		t.Fatalf("expected host 7 to be adopted, got %v", host)
+	}
+
+	// the server being replaced with -replace is scheduled for release before the create
+	hosts = append(hosts, scgo.Host{ID: "8", Type: "dedicated_server", Title: "node-2", Labels: labels, Status: "init", ScheduledRelease: &released})
+
+	if host := findOrphanedHost(hosts, "dedicated_server", "node-2", "key"); host != nil {
+		t.Fatalf("expected dedicated server scheduled for release not to be adopted, got %s", host.ID)
+	}
+}
+
+func TestSetHostLabel(t *testing.T) {
+	labels := map[string]string{"env": "test"}
+	host := setHostLabel(scgo.DedicatedServerHostInput{Hostname: "node-1", Labels: labels}, idempotencyLabel, "key").(scgo.DedicatedServerHostInput)
+
+	if host.Labels["env"] != "test" || host.Labels[idempotencyLabel] != "key" {
+		t.Fatalf("unexpected labels: %v", host.Labels)
+	}
+	if _, ok := labels[idempotencyLabel]; ok {
+		t.Fatal("expected the original labels to be left intact")
+	}
+}
+
+func TestOrderServer_Cancelled(t *testing.T) {
+	// the batch window is never reached, the request waits for the cancellation only
+	sc, _ := testServerCollector(ServerCollectorConfig{Window: time.Hour})
+	meta := &ProviderMeta{
+		Client:          testFakeAPIClient(t),
+		ServerCollector: sc,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := orderServer(ctx, meta, "sbm", testSBMRequest("node-1").Input)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the cancelled order to return right away, it took %s", elapsed)
+	}
+}
+
+func TestOrderServer_Adopted(t *testing.T) {
+	ctx := context.Background()
+	client := testFakeAPIClient(t)
+	sc, _ := testServerCollector(ServerCollectorConfig{Window: time.Hour})
+	meta := &ProviderMeta{
+		Client:          client,
+		ServerCollector: sc,
+	}
+
+	key, err := idempotencyKey("node-1", testSBMRequest("node-1").Input)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	orphaned := testCreateSBMServer(t, client, "node-1", map[string]string{idempotencyLabel: key})
+
+	id, err := orderServer(ctx, meta, "sbm", testSBMRequest("node-1").Input)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if id != orphaned {
+		t.Fatalf("expected server %s to be adopted, got %s", orphaned, id)
+	}
+}
+
+func TestOrderServer_ReplacementOrdersNewServer(t *testing.T) {
+	client := testFakeAPIClient(t)
+	sc, batches := testServerCollector(ServerCollectorConfig{})
+	meta := &ProviderMeta{
+		Client:          client,
+		ServerCollector: sc,
+	}
+
+	key, err := idempotencyKey("node-1", testSBMRequest("node-1").Input)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the server of the instance being replaced, e.g. with create_before_destroy, has the same key
+	current := testCreateSBMServer(t, client, "node-1", map[string]string{idempotencyLabel: key})
+	for i := 0; i < 3; i++ {
+		if _, err := client.Hosts.GetSBMServer(context.Background(), current); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := orderServer(ctx, meta, "sbm", testSBMRequest("node-1").Input)
+		done <- err
+	}()
+
+	select {
+	case batch := <-batches:
+		if len(batch) != 1 {
+			t.Fatalf("expected one request, got %d", len(batch))
+		}
+	case err := <-done:
+		t.Fatalf("expected a new server to be ordered instead of adopting the active one, got %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a new server to be ordered")
+	}
+
+	// the test collector doesn't answer the request
+	cancel()
+	<-done
+}
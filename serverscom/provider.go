@@ -1,7 +1,10 @@
 package serverscom
 
 import (
+	"net/http"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
@@ -18,14 +21,48 @@ func Provider() *schema.Provider {
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("SERVERSCOM_API_URL", "https://api.servers.com/v1"),
 			},
+			"server_batch_window": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultServerBatchWindow.String(),
+				ValidateFunc: validateDuration,
+				Description:  "Time without new server create requests after which they are ordered in one batch, 0 disables batching",
+			},
+			"server_batch_max_wait": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultServerBatchMaxWait.String(),
+				ValidateFunc: validateDuration,
+				Description:  "Maximum time the first server create request waits for the batch, 0 means no limit",
+			},
+			"server_batch_max_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultServerBatchMaxSize,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of servers ordered in one batch, 0 means no limit",
+			},
+			"retry": retrySchema(),
+			"cache": cacheSchema(),
+			"default_labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Labels merged into the labels of every resource that supports them",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"serverscom_network_pool":                       datasourceServerscomNetworkPool(),
 			"serverscom_dedicated_server":                   dataSourceServerscomDedicatedServer(),
+			"serverscom_dedicated_servers":                  dataSourceServerscomDedicatedServers(),
 			"serverscom_sbm_server":                         dataSourceServerscomSBMServer(),
+			"serverscom_sbm_servers":                        dataSourceServerscomSBMServers(),
 			"serverscom_l2_segment":                         dataSourceServerscomL2Segment(),
 			"serverscom_l2_segment_members":                 dataSourceServerscomL2SegmentMembers(),
 			"serverscom_cloud_computing_instance":           dataSourceServerscomCloudInstance(),
+			"serverscom_cloud_computing_instances":          dataSourceServerscomCloudInstances(),
 			"serverscom_location":                           dataSourceServerscomLocation(),
 			"serverscom_locations":                          dataSourceServerscomLocations(),
 			"serverscom_server_model_order_option":          dataSourceServerscomServerModelOrderOption(),
@@ -58,22 +95,56 @@ func Provider() *schema.Provider {
 			"serverscom_subnetwork":               resourceServerscomSubnetwork(),
 			"serverscom_sbm_server":               resourceServerscomSBM(),
 			"serverscom_rbs_volume":               resourceServerscomRBSVolume(),
+			"serverscom_ptr_record":               resourceServerscomPTRRecord(),
+			"serverscom_dedicated_server_network": resourceServerscomDedicatedServerNetwork(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
 }
 
+// ProviderMeta holds the state of a single configured provider instance.
+// Each aliased provider gets its own client, catalog cache and server collector,
+// so resources never mix up accounts.
+type ProviderMeta struct {
+	Client          *scgo.Client
+	Cache           *Cache
+	ServerCollector *ServerCollector
+	DefaultLabels   map[string]string
+}
+
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	retryConfig, err := expandRetryConfig(d)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheConfig, err := expandCacheConfig(d)
+	if err != nil {
+		return nil, err
+	}
+
+	serverCollectorConfig, err := expandServerCollectorConfig(d)
+	if err != nil {
+		return nil, err
+	}
+
 	client := scgo.NewClientWithEndpoint(
 		d.Get("token").(string),
 		d.Get("endpoint").(string),
 	)
 
 	client.SetupUserAgent("terraform-provider-serverscom")
-	cache = NewCache(client)
+	client.SetHTTPClient(&http.Client{
+		Transport: newRetryTransport(nil, retryConfig),
+	})
 
-	serverCollector = NewServerCollector(client)
+	serverCollector := NewServerCollector(client, serverCollectorConfig)
 	serverCollector.Run()
 
-	return client, nil
+	return &ProviderMeta{
+		Client:          client,
+		Cache:           NewCache(client, cacheConfig),
+		ServerCollector: serverCollector,
+		DefaultLabels:   toStringMap(d.Get("default_labels").(map[string]interface{})),
+	}, nil
 }
@@ -27,6 +27,35 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestProviderConfigure_separateMeta(t *testing.T) {
+	configure := func(token string) *ProviderMeta {
+		raw := map[string]interface{}{
+			"token":    token,
+			"endpoint": "https://api.servers.com/v1",
+		}
+
+		meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().Schema, raw))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		return meta.(*ProviderMeta)
+	}
+
+	first := configure("first-token")
+	second := configure("second-token")
+
+	if first.Client == second.Client {
+		t.Fatal("expected each provider instance to have its own client")
+	}
+	if first.Cache == second.Cache {
+		t.Fatal("expected each provider instance to have its own cache")
+	}
+	if first.ServerCollector == second.ServerCollector {
+		t.Fatal("expected each provider instance to have its own server collector")
+	}
+}
+
 func testAccServerscomPreCheck(t *testing.T) {
 	if v := os.Getenv("SERVERSCOM_API_TOKEN"); v == "" {
 		t.Fatal("SERVERSCOM_API_TOKEN must be set for acceptance tests")
@@ -2,11 +2,13 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
 	"strconv"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -19,12 +21,12 @@ var (
 
 func resourceServerscomCloudComputingInstance() *schema.Resource {
 	return &schema.Resource{
-		Read:   resourceServerscomCloudComputingInstanceRead,
-		Update: resourceServerscomCloudComputingInstanceUpdate,
-		Delete: resourceServerscomCloudComputingInstanceDelete,
-		Create: resourceServerscomCloudComputingInstanceCreate,
+		ReadContext:   resourceServerscomCloudComputingInstanceRead,
+		UpdateContext: resourceServerscomCloudComputingInstanceUpdate,
+		DeleteContext: resourceServerscomCloudComputingInstanceDelete,
+		CreateContext: resourceServerscomCloudComputingInstanceCreate,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importByReference("cloud computing instance", cloudComputingInstanceLookup, resourceServerscomCloudComputingInstance),
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -33,6 +35,12 @@ func resourceServerscomCloudComputingInstance() *schema.Resource {
 			Delete: schema.DefaultTimeout(serverscomCloudComputingInstanceDefaultTimeout),
 		},
 
+		CustomizeDiff: customdiff.All(
+			customizeDiffLabelsAll,
+			customizeDiffCloudInstanceOrderOptions,
+			customizeDiffDeletionProtection("cloud computing instance", resourceServerscomCloudComputingInstance),
+		),
+
 		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
@@ -43,21 +51,48 @@ func resourceServerscomCloudComputingInstance() *schema.Resource {
 			},
 			"region": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
 				DiffSuppressFunc: compareStrings,
 				ValidateFunc:     validation.NoZeroValues,
+				ExactlyOneOf:     []string{"region", "region_id"},
+			},
+			"region_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"region", "region_id"},
 			},
 			"image": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
 				DiffSuppressFunc: compareStrings,
 				ValidateFunc:     validation.NoZeroValues,
+				ExactlyOneOf:     []string{"image", "image_id"},
+			},
+			"image_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"image", "image_id"},
 			},
 			"flavor": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
 				DiffSuppressFunc: compareStrings,
 				ValidateFunc:     validation.NoZeroValues,
+				ExactlyOneOf:     []string{"flavor", "flavor_id"},
+			},
+			"flavor_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"flavor", "flavor_id"},
 			},
 			"gpn_enabled": {
 				Type:     schema.TypeBool,
@@ -121,24 +156,28 @@ func resourceServerscomCloudComputingInstance() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"labels_all":          labelsAllSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 		},
 	}
 }
 
-func resourceServerscomCloudComputingInstanceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-
-	ctx := context.TODO()
+func resourceServerscomCloudComputingInstanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	cloudInstance, err := client.CloudComputingInstances.Get(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.Set("status", cloudInstance.Status)
 	d.Set("name", cloudInstance.Name)
+	d.Set("region", cloudInstance.RegionCode)
+	d.Set("region_id", cloudInstance.RegionID)
 	d.Set("image", cloudInstance.ImageName)
+	d.Set("image_id", cloudInstance.ImageID)
 	d.Set("flavor", cloudInstance.FlavorName)
+	d.Set("flavor_id", cloudInstance.FlavorID)
 	d.Set("private_ipv4_address", cloudInstance.PrivateIPv4Address)
 	d.Set("public_ipv4_address", cloudInstance.PublicIPv4Address)
 	d.Set("public_ipv6_address", cloudInstance.PublicIPv6Address)
@@ -146,7 +185,10 @@ func resourceServerscomCloudComputingInstanceRead(d *schema.ResourceData, meta i
 	d.Set("ipv4_enabled", cloudInstance.PublicIPv4Address)
 	d.Set("gpn_enabled", cloudInstance.GPNEnabled)
 	d.Set("openstack_uuid", cloudInstance.OpenstackUUID)
-	d.Set("labels", cloudInstance.Labels)
+
+	if err := setLabels(d, cloudInstance.Labels, meta); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if cloudInstance.PublicIPv4Address != nil {
 		d.SetConnInfo(map[string]string{
@@ -158,10 +200,11 @@ func resourceServerscomCloudComputingInstanceRead(d *schema.ResourceData, meta i
 	return nil
 }
 
-func resourceServerscomCloudComputingInstanceUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceServerscomCloudComputingInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var err error
 
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
+	cache := meta.(*ProviderMeta).Cache
 	hasChanges := false
 
 	// update
@@ -188,24 +231,15 @@ func resourceServerscomCloudComputingInstanceUpdate(d *schema.ResourceData, meta
 		updateInput.GPNEnabled = &gpnEnabled
 	}
 
-	if d.HasChange("labels") {
+	if d.HasChanges("labels", "labels_all") {
 		hasChanges = true
-		if labelsRaw, ok := d.GetOk("labels"); ok {
-			labels := labelsRaw.(map[string]interface{})
-			stringLabels := make(map[string]string)
-			for k, v := range labels {
-				stringLabels[k] = v.(string)
-			}
-			updateInput.Labels = stringLabels
-		}
+		updateInput.Labels = expandLabels(d, meta)
 	}
 
-	ctx := context.TODO()
-
 	if hasChanges {
 		_, err = client.CloudComputingInstances.Update(ctx, d.Id(), updateInput)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -213,15 +247,17 @@ func resourceServerscomCloudComputingInstanceUpdate(d *schema.ResourceData, meta
 	hasChanges = false
 	upgradeInput := scgo.CloudComputingInstanceUpgradeInput{}
 
-	if d.HasChange("flavor") {
+	if d.HasChanges("flavor", "flavor_id") {
 		hasChanges = true
-		region, err := getRegion(d.Get("region").(string))
+		regionRef := getOptionRef(d, "region", "region_id")
+		region, err := getRegion(ctx, cache, regionRef)
 		if err != nil {
-			return err
+			return optionDiagnostics(err, regionRef.Path)
 		}
-		flavor, err := getFlavor(region.ID, d.Get("flavor").(string))
+		flavorRef := getOptionRef(d, "flavor", "flavor_id")
+		flavor, err := getFlavor(ctx, cache, region.ID, flavorRef)
 		if err != nil {
-			return err
+			return optionDiagnostics(err, flavorRef.Path)
 		}
 
 		upgradeInput.FlavorID = flavor.ID
@@ -230,17 +266,19 @@ func resourceServerscomCloudComputingInstanceUpdate(d *schema.ResourceData, meta
 	if hasChanges {
 		_, err = client.CloudComputingInstances.Upgrade(ctx, d.Id(), upgradeInput)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceServerscomCloudComputingInstanceRead(d, meta)
+	return resourceServerscomCloudComputingInstanceRead(ctx, d, meta)
 }
 
-func resourceServerscomCloudComputingInstanceDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
+func resourceServerscomCloudComputingInstanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := checkDeletionProtection(d, "cloud computing instance"); err != nil {
+		return diag.FromErr(err)
+	}
 
-	ctx := context.TODO()
+	client := meta.(*ProviderMeta).Client
 
 	cloudInstance, err := client.CloudComputingInstances.Get(ctx, d.Id())
 	if err != nil {
@@ -250,7 +288,7 @@ func resourceServerscomCloudComputingInstanceDelete(d *schema.ResourceData, meta
 			d.SetId("")
 			return nil
 		default:
-			return fmt.Errorf("Error retrieving cloud computing instance: %s", err.Error())
+			return diag.Errorf("Error retrieving cloud computing instance: %s", err.Error())
 		}
 	}
 
@@ -260,32 +298,36 @@ func resourceServerscomCloudComputingInstanceDelete(d *schema.ResourceData, meta
 		return nil
 	}
 
-	return client.CloudComputingInstances.Delete(ctx, d.Id())
+	return diag.FromErr(client.CloudComputingInstances.Delete(ctx, d.Id()))
 }
 
-func resourceServerscomCloudComputingInstanceCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
+func resourceServerscomCloudComputingInstanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
+	cache := meta.(*ProviderMeta).Cache
 
 	input := scgo.CloudComputingInstanceCreateInput{}
 	input.Name = d.Get("name").(string)
 
-	region, err := getRegion(d.Get("region").(string))
+	regionRef := getOptionRef(d, "region", "region_id")
+	region, err := getRegion(ctx, cache, regionRef)
 	if err != nil {
-		return err
+		return optionDiagnostics(err, regionRef.Path)
 	}
 
 	input.RegionID = region.ID
 
-	flavor, err := getFlavor(region.ID, d.Get("flavor").(string))
+	flavorRef := getOptionRef(d, "flavor", "flavor_id")
+	flavor, err := getFlavor(ctx, cache, region.ID, flavorRef)
 	if err != nil {
-		return err
+		return optionDiagnostics(err, flavorRef.Path)
 	}
 
 	input.FlavorID = flavor.ID
 
-	image, err := getImage(region.ID, d.Get("image").(string))
+	imageRef := getOptionRef(d, "image", "image_id")
+	image, err := getImage(ctx, cache, region.ID, imageRef)
 	if err != nil {
-		return err
+		return optionDiagnostics(err, imageRef.Path)
 	}
 
 	input.ImageID = image.ID
@@ -311,25 +353,16 @@ func resourceServerscomCloudComputingInstanceCreate(d *schema.ResourceData, meta
 		input.SSHKeyFingerprint = &sshKeyFp
 	}
 
-	if labelsRaw, ok := d.GetOk("labels"); ok {
-		labels := labelsRaw.(map[string]interface{})
-		stringLabels := make(map[string]string)
-		for k, v := range labels {
-			stringLabels[k] = v.(string)
-		}
-		input.Labels = stringLabels
-	}
+	input.Labels = expandLabels(d, meta)
 
 	if v, ok := d.GetOk("user_data"); ok {
 		userData := v.(string)
 		input.UserData = &userData
 	}
 
-	ctx := context.TODO()
-
 	cloudInstance, err := client.CloudComputingInstances.Create(ctx, input)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(cloudInstance.ID)
@@ -337,7 +370,7 @@ func resourceServerscomCloudComputingInstanceCreate(d *schema.ResourceData, meta
 	pending := []string{"CREATING", "PENDING", "PROVISIONING", "BUILDING", "REBOOTING"}
 	_, err = waitForCloudComputingInstanceAttribute(ctx, d, "ACTIVE", pending, "status", meta, schema.TimeoutCreate)
 	if err != nil {
-		return fmt.Errorf("Error waiting for cloud computing instance (%s) to become active: %s", d.Id(), err)
+		return diag.Errorf("Error waiting for cloud computing instance (%s) to become active: %s", d.Id(), err)
 	}
 
 	return nil
@@ -352,20 +385,20 @@ func waitForCloudComputingInstanceAttribute(ctx context.Context, d *schema.Resou
 	stateConf := &retry.StateChangeConf{
 		Pending:    pending,
 		Target:     []string{target},
-		Refresh:    newCloudComputingInstanceStateRefreshFunc(d, attribute, meta),
+		Refresh:    newCloudComputingInstanceStateRefreshFunc(ctx, d, attribute, meta),
 		Timeout:    d.Timeout(timeoutKey),
-		Delay:      1 * time.Minute,
-		MinTimeout: 3 * time.Second,
+		Delay:      waiterDelay(1 * time.Minute),
+		MinTimeout: waiterDelay(3 * time.Second),
 	}
 
 	return stateConf.WaitForStateContext(ctx)
 }
 
-func newCloudComputingInstanceStateRefreshFunc(d *schema.ResourceData, attribute string, meta interface{}) retry.StateRefreshFunc {
+func newCloudComputingInstanceStateRefreshFunc(ctx context.Context, d *schema.ResourceData, attribute string, meta interface{}) retry.StateRefreshFunc {
 	return func() (interface{}, string, error) {
-		err := resourceServerscomCloudComputingInstanceRead(d, meta)
-		if err != nil {
-			return nil, "", err
+		diags := resourceServerscomCloudComputingInstanceRead(ctx, d, meta)
+		if diags.HasError() {
+			return nil, "", errors.New(diags[0].Summary)
 		}
 
 		// See if we can access our attribute
@@ -382,47 +415,57 @@ func newCloudComputingInstanceStateRefreshFunc(d *schema.ResourceData, attribute
 	}
 }
 
-func getRegion(code string) (*scgo.CloudComputingRegion, error) {
-	regions, err := cache.CloudComputingRegions()
+func getRegion(ctx context.Context, cache *Cache, ref optionRef) (*scgo.CloudComputingRegion, error) {
+	regions, err := cache.CloudComputingRegions(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, region := range regions {
-		if normalizeString(region.Code) == normalizeString(code) {
+		id := strconv.FormatInt(region.ID, 10)
+		valid = append(valid, ref.label(id, region.Code))
+
+		if ref.matches(id, region.Code) {
 			return &region, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find cloud computing region by: %s", code)
+	return nil, ref.notFound("cloud computing region", valid)
 }
 
-func getFlavor(regionID int64, name string) (*scgo.CloudComputingFlavor, error) {
-	flavors, err := cache.CloudComputingFlavors(regionID)
+func getFlavor(ctx context.Context, cache *Cache, regionID int64, ref optionRef) (*scgo.CloudComputingFlavor, error) {
+	flavors, err := cache.CloudComputingFlavors(ctx, regionID)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, flavor := range flavors {
-		if normalizeString(flavor.Name) == normalizeString(name) {
+		valid = append(valid, ref.label(flavor.ID, flavor.Name))
+
+		if ref.matches(flavor.ID, flavor.Name) {
 			return &flavor, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find cloud computing flavor by: %s", name)
+	return nil, ref.notFound("cloud computing flavor", valid)
 }
 
-func getImage(regionID int64, name string) (*scgo.CloudComputingImage, error) {
-	images, err := cache.CloudComputingImages(regionID)
+func getImage(ctx context.Context, cache *Cache, regionID int64, ref optionRef) (*scgo.CloudComputingImage, error) {
+	images, err := cache.CloudComputingImages(ctx, regionID)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, image := range images {
-		if normalizeString(image.Name) == normalizeString(name) {
+		valid = append(valid, ref.label(image.ID, image.Name))
+
+		if ref.matches(image.ID, image.Name) {
 			return &image, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find cloud computing image by: %s", name)
+	return nil, ref.notFound("cloud computing image", valid)
 }
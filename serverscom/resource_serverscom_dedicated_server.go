@@ -2,12 +2,16 @@ package serverscom
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sort"
 	"strconv"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -17,23 +21,35 @@ import (
 var (
 	serverscomDedicatedServerDefaultCreateTimeout = 24 * time.Hour
 	serverscomDedicatedServerDefaultDeleteTimeout = 1 * time.Hour
+	serverscomDedicatedServerDefaultUpdateTimeout = 6 * time.Hour
 )
 
 func resourceServerscomDedicatedServer() *schema.Resource {
 	return &schema.Resource{
-		Read:   resourceServerscomDedicatedServerRead,
-		Update: resourceServerscomDedicatedServerUpdate,
-		Delete: resourceServerscomDedicatedServerDelete,
-		Create: resourceServerscomDedicatedServerCreate,
+		ReadContext:   resourceServerscomDedicatedServerRead,
+		UpdateContext: resourceServerscomDedicatedServerUpdate,
+		DeleteContext: resourceServerscomDedicatedServerDelete,
+		CreateContext: resourceServerscomDedicatedServerCreate,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importByReference("dedicated server", hostLookup("dedicated_server"), resourceServerscomDedicatedServer),
 		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(serverscomDedicatedServerDefaultCreateTimeout),
 			Delete: schema.DefaultTimeout(serverscomDedicatedServerDefaultDeleteTimeout),
+			Update: schema.DefaultTimeout(serverscomDedicatedServerDefaultUpdateTimeout),
 		},
 
+		CustomizeDiff: customdiff.All(
+			customizeDiffLabelsAll,
+			customizeDiffDedicatedServerDrives,
+			customizeDiffDedicatedServerOrderOptions,
+			customizeDiffDedicatedServerReinstall,
+			customizeDiffDedicatedServerFeatures,
+			customizeDiffDedicatedServerRelease,
+			customizeDiffDeletionProtection("dedicated server", resourceServerscomDedicatedServer),
+		),
+
 		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
@@ -44,17 +60,37 @@ func resourceServerscomDedicatedServer() *schema.Resource {
 			},
 			"location": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
 				ForceNew:         true,
 				DiffSuppressFunc: compareStrings,
 				ValidateFunc:     validation.NoZeroValues,
+				ExactlyOneOf:     []string{"location", "location_id"},
+			},
+			"location_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"location", "location_id"},
 			},
 			"server_model": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
 				ForceNew:         true,
 				DiffSuppressFunc: compareStrings,
 				ValidateFunc:     validation.NoZeroValues,
+				ExactlyOneOf:     []string{"server_model", "server_model_id"},
+			},
+			"server_model_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"server_model", "server_model_id"},
 			},
 			"ram_size": {
 				Type:     schema.TypeInt,
@@ -63,26 +99,63 @@ func resourceServerscomDedicatedServer() *schema.Resource {
 			"operating_system": {
 				Type:             schema.TypeString,
 				Optional:         true,
+				Computed:         true,
 				DiffSuppressFunc: compareStrings,
+				ConflictsWith:    []string{"operating_system_id"},
+			},
+			"operating_system_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ValidateFunc:  validation.IntAtLeast(1),
+				ConflictsWith: []string{"operating_system"},
 			},
 			"public_uplink": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"public_uplink_id"},
+			},
+			"public_uplink_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ValidateFunc:  validation.IntAtLeast(1),
+				ConflictsWith: []string{"public_uplink"},
 			},
 			"private_uplink": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
 				DiffSuppressFunc: compareStrings,
 				ValidateFunc:     validation.NoZeroValues,
+				ExactlyOneOf:     []string{"private_uplink", "private_uplink_id"},
+			},
+			"private_uplink_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"private_uplink", "private_uplink_id"},
 			},
 			"bandwidth": {
 				Type:             schema.TypeString,
 				Optional:         true,
+				Computed:         true,
 				DiffSuppressFunc: compareStrings,
+				ConflictsWith:    []string{"bandwidth_id"},
+			},
+			"bandwidth_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ValidateFunc:  validation.IntAtLeast(1),
+				ConflictsWith: []string{"bandwidth"},
 			},
 			"slot": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"position": {
@@ -93,14 +166,22 @@ func resourceServerscomDedicatedServer() *schema.Resource {
 						"drive_model": {
 							Type:             schema.TypeString,
 							Optional:         true,
+							Computed:         true,
 							DiffSuppressFunc: compareStrings,
 						},
+						"drive_model_id": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
 					},
 				},
 			},
 			"layout": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"slot_positions": {
@@ -125,8 +206,9 @@ func resourceServerscomDedicatedServer() *schema.Resource {
 										Required: true,
 									},
 									"size": {
-										Type:     schema.TypeInt,
-										Required: true,
+										Type:             schema.TypeInt,
+										Required:         true,
+										DiffSuppressFunc: suppressFilledPartitionSize,
 									},
 									"fill": {
 										Type:     schema.TypeBool,
@@ -143,11 +225,40 @@ func resourceServerscomDedicatedServer() *schema.Resource {
 					},
 				},
 			},
+			"layout_reported": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the api reports the drive layout of the server, the configured layout is stored as is otherwise",
+			},
 			"ssh_key_fingerprints": {
 				Type:     schema.TypeList,
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"power_state": powerStateSchema(),
+			"features":    dedicatedServerFeaturesSchema(),
+			"reinstall_on_change": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow changes of operating_system, layout and ssh_key_fingerprints, which reinstall the operating system and wipe the server",
+			},
+			"release_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      releaseModeEndOfBillingPeriod,
+				ValidateFunc: validation.StringInSlice([]string{releaseModeEndOfBillingPeriod, releaseModeDate}, false),
+				Description:  "When the server is released on destroy, at the end of the billing period or at release_after",
+			},
+			"release_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"scheduled_release_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"ipv6": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -189,6 +300,50 @@ func resourceServerscomDedicatedServer() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"networks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"title": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cidr": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"gateway": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"family": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"interface_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"distribution_method": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"additional": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"labels": {
 				Type:     schema.TypeMap,
 				Optional: true,
@@ -196,14 +351,14 @@ func resourceServerscomDedicatedServer() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"labels_all":          labelsAllSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 		},
 	}
 }
 
-func resourceServerscomDedicatedServerRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-
-	ctx := context.TODO()
+func resourceServerscomDedicatedServerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	dedicatedServer, err := client.Hosts.GetDedicatedServer(ctx, d.Id())
 	if err != nil {
@@ -213,14 +368,12 @@ func resourceServerscomDedicatedServerRead(d *schema.ResourceData, meta interfac
 			d.SetId("")
 			return nil
 		default:
-			return fmt.Errorf("Error retrieving dedicated server: %s", err)
+			return diag.Errorf("Error retrieving dedicated server: %s", err)
 		}
 	}
 
 	if dedicatedServer.ScheduledRelease != nil {
 		log.Printf("[WARN] Serverscom dedicated server (%s) marked as scheduled to release", d.Id())
-		d.SetId("")
-		return nil
 	}
 
 	d.Set("hostname", dedicatedServer.Title)
@@ -228,14 +381,25 @@ func resourceServerscomDedicatedServerRead(d *schema.ResourceData, meta interfac
 	d.Set("private_ipv4_address", dedicatedServer.PrivateIPv4Address)
 	d.Set("public_ipv4_address", dedicatedServer.PublicIPv4Address)
 	d.Set("status", dedicatedServer.Status)
+	d.Set("scheduled_release_at", formatScheduledRelease(dedicatedServer.ScheduledRelease))
+	setPowerState(d, dedicatedServer.PowerStatus)
 	d.Set("server_model", dedicatedServer.ConfigurationDetails.ServerModelName)
+	d.Set("server_model_id", dedicatedServer.ConfigurationDetails.ServerModelID)
 	d.Set("public_uplink", dedicatedServer.ConfigurationDetails.PublicUplinkName)
+	d.Set("public_uplink_id", dedicatedServer.ConfigurationDetails.PublicUplinkID)
 	d.Set("private_uplink", dedicatedServer.ConfigurationDetails.PrivateUplinkName)
+	d.Set("private_uplink_id", dedicatedServer.ConfigurationDetails.PrivateUplinkID)
 	d.Set("bandwidth", dedicatedServer.ConfigurationDetails.BandwidthName)
+	d.Set("bandwidth_id", dedicatedServer.ConfigurationDetails.BandwidthID)
 	d.Set("operating_system", dedicatedServer.ConfigurationDetails.OperatingSystemFullName)
+	d.Set("operating_system_id", dedicatedServer.ConfigurationDetails.OperatingSystemID)
 	d.Set("ram_size", dedicatedServer.ConfigurationDetails.RAMSize)
 	d.Set("location", dedicatedServer.LocationCode)
-	d.Set("labels", dedicatedServer.Labels)
+	d.Set("location_id", dedicatedServer.LocationID)
+
+	if err := setLabels(d, dedicatedServer.Labels, meta); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if dedicatedServer.Status != "active" {
 		return nil
@@ -243,7 +407,7 @@ func resourceServerscomDedicatedServerRead(d *schema.ResourceData, meta interfac
 
 	slots, err := client.Hosts.DedicatedServerDriveSlots(d.Id()).Collect(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	driveSlots := getDriveSlots(slots)
@@ -257,6 +421,33 @@ func resourceServerscomDedicatedServerRead(d *schema.ResourceData, meta interfac
 
 	d.Set("slot", driveSlots)
 
+	// the layout is unknown when the api doesn't report it, the one in the state is kept then
+	layouts, err := client.Hosts.DedicatedServerLayouts(d.Id()).Collect(ctx)
+	switch err.(type) {
+	case nil:
+		d.Set("layout", flattenLayouts(hostLayoutsInput(layouts)))
+		d.Set("layout_reported", true)
+	case *scgo.NotFoundError:
+		d.Set("layout_reported", false)
+		log.Printf("[WARN] Serverscom dedicated server (%s) drive layouts aren't available, keeping the layout as is", d.Id())
+	default:
+		return diag.FromErr(err)
+	}
+
+	networks, err := client.Hosts.DedicatedServerNetworks(d.Id()).Collect(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("networks", getDedicatedServerNetworks(networks))
+
+	features, err := client.Hosts.DedicatedServerFeatures(d.Id()).Collect(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("features", flattenDedicatedServerFeatures(d.Get("features"), features))
+
 	if dedicatedServer.PublicIPv4Address != nil {
 		d.SetConnInfo(map[string]string{
 			"type": "ssh",
@@ -267,20 +458,21 @@ func resourceServerscomDedicatedServerRead(d *schema.ResourceData, meta interfac
 	return nil
 }
 
-func resourceServerscomDedicatedServerUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceServerscomDedicatedServerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
+
 	input := scgo.DedicatedServerUpdateInput{}
 
 	hasChanges := false
-	if d.HasChange("labels") {
+	if d.HasChanges("labels", "labels_all") {
 		hasChanges = true
-		if labelsRaw, ok := d.GetOk("labels"); ok {
-			labels := labelsRaw.(map[string]interface{})
-			stringLabels := make(map[string]string)
-			for k, v := range labels {
-				stringLabels[k] = v.(string)
-			}
-			input.Labels = stringLabels
+
+		dedicatedServer, err := client.Hosts.GetDedicatedServer(ctx, d.Id())
+		if err != nil {
+			return diag.Errorf("Error retrieving dedicated server: %s", err)
 		}
+
+		input.Labels = keepIdempotencyLabel(expandLabels(d, meta), dedicatedServer.Labels)
 	}
 
 	if d.HasChange("hostname") {
@@ -291,22 +483,53 @@ func resourceServerscomDedicatedServerUpdate(d *schema.ResourceData, meta interf
 	}
 
 	if hasChanges {
-		client := meta.(*scgo.Client)
-		ctx := context.TODO()
-
 		if _, err := client.Hosts.UpdateDedicatedServer(ctx, d.Id(), input); err != nil {
-			return err
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("scheduled_release_at") {
+		hasChanges = true
+		if err := abortDedicatedServerRelease(ctx, client, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if len(dedicatedServerReinstallChanges(d)) > 0 {
+		hasChanges = true
+		if diags := reinstallDedicatedServer(ctx, d, meta); diags.HasError() {
+			return diags
 		}
+	}
 
-		return resourceServerscomDedicatedServerRead(d, meta)
+	if d.HasChange("features") {
+		hasChanges = true
+		oldFeatures, newFeatures := d.GetChange("features")
+		if err := applyDedicatedServerFeatures(ctx, d, meta, expandDedicatedServerFeatures(oldFeatures), expandDedicatedServerFeatures(newFeatures), schema.TimeoutUpdate); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("power_state") {
+		hasChanges = true
+		if err := applyPowerState(ctx, d, dedicatedServerPower(client), d.Get("power_state").(string), schema.TimeoutUpdate); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if hasChanges {
+		return resourceServerscomDedicatedServerRead(ctx, d, meta)
 	}
 
 	return nil
 }
 
-func resourceServerscomDedicatedServerDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func resourceServerscomDedicatedServerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := checkDeletionProtection(d, "dedicated server"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*ProviderMeta).Client
 
 	dedicatedServer, err := client.Hosts.GetDedicatedServer(ctx, d.Id())
 	if err != nil {
@@ -316,7 +539,7 @@ func resourceServerscomDedicatedServerDelete(d *schema.ResourceData, meta interf
 			d.SetId("")
 			return nil
 		default:
-			return fmt.Errorf("Error retrieving dedicated server: %s", err.Error())
+			return diag.Errorf("Error retrieving dedicated server: %s", err.Error())
 		}
 	}
 
@@ -329,18 +552,18 @@ func resourceServerscomDedicatedServerDelete(d *schema.ResourceData, meta interf
 	if dedicatedServer.Status == "pending" || dedicatedServer.Status == "init" {
 		_, err = waitForDedicatedServerAttribute(ctx, d, "active", []string{"init", "pending"}, "status", meta, schema.TimeoutDelete)
 		if err != nil {
-			return fmt.Errorf("Error waiting for dedicated server (%s) to become ready: %s", d.Id(), err)
+			return diag.Errorf("Error waiting for dedicated server (%s) to become ready: %s", d.Id(), err)
 		}
 	}
 
-	if _, err := client.Hosts.ScheduleReleaseForDedicatedServer(ctx, d.Id(), scgo.ScheduleReleaseInput{}); err != nil {
-		return err
+	if _, err := client.Hosts.ScheduleReleaseForDedicatedServer(ctx, d.Id(), expandScheduleReleaseInput(d)); err != nil {
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceServerscomDedicatedServerCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceServerscomDedicatedServerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var (
 		location        *scgo.Location
 		serverModel     *scgo.ServerModelOption
@@ -357,6 +580,7 @@ func resourceServerscomDedicatedServerCreate(d *schema.ResourceData, meta interf
 		err error
 	)
 
+	cache := meta.(*ProviderMeta).Cache
 	input := &DedicatedServerCreateInput{}
 
 	if id, ok := d.GetOk("public_ipv4_network_id"); ok {
@@ -377,25 +601,20 @@ func resourceServerscomDedicatedServerCreate(d *schema.ResourceData, meta interf
 			PrivateIPv4NetworkID: privateIpv4NetworkId,
 		},
 	}
-	if labelsRaw, ok := d.GetOk("labels"); ok {
-		labels := labelsRaw.(map[string]interface{})
-		stringLabels := make(map[string]string)
-		for k, v := range labels {
-			stringLabels[k] = v.(string)
-		}
-		input.Hosts[0].Labels = stringLabels
-	}
+	input.Hosts[0].Labels = expandLabels(d, meta)
 
-	location, err = getLocation(d.Get("location").(string))
+	locationRef := getOptionRef(d, "location", "location_id")
+	location, err = getLocation(ctx, cache, locationRef)
 	if err != nil {
-		return err
+		return optionDiagnostics(err, locationRef.Path)
 	}
 
 	input.LocationID = location.ID
 
-	serverModel, err = getServerModel(location.ID, d.Get("server_model").(string))
+	serverModelRef := getOptionRef(d, "server_model", "server_model_id")
+	serverModel, err = getServerModel(ctx, cache, location.ID, serverModelRef)
 	if err != nil {
-		return err
+		return optionDiagnostics(err, serverModelRef.Path)
 	}
 
 	input.ServerModelID = serverModel.ID
@@ -406,10 +625,10 @@ func resourceServerscomDedicatedServerCreate(d *schema.ResourceData, meta interf
 		input.RAMSize = serverModel.RAM
 	}
 
-	if operatingSystemName, ok := d.GetOk("operating_system"); ok {
-		operatingSystem, err = getOperatingSystem(location.ID, serverModel.ID, operatingSystemName.(string))
+	if operatingSystemRef := getOptionRef(d, "operating_system", "operating_system_id"); !operatingSystemRef.IsEmpty() {
+		operatingSystem, err = getOperatingSystem(ctx, cache, location.ID, serverModel.ID, operatingSystemRef)
 		if err != nil {
-			return err
+			return optionDiagnostics(err, operatingSystemRef.Path)
 		}
 
 		input.OperatingSystemID = &operatingSystem.ID
@@ -417,49 +636,67 @@ func resourceServerscomDedicatedServerCreate(d *schema.ResourceData, meta interf
 
 	input.UplinkModels = scgo.DedicatedServerUplinkModelsInput{}
 
-	if publicUplinkName, ok := d.GetOk("public_uplink"); ok {
-		publicUplink, err = getUplink(location.ID, serverModel.ID, publicUplinkName.(string))
+	if publicUplinkRef := getOptionRef(d, "public_uplink", "public_uplink_id"); !publicUplinkRef.IsEmpty() {
+		publicUplink, err = getUplink(ctx, cache, location.ID, serverModel.ID, publicUplinkRef)
 		if err != nil {
-			return err
+			return optionDiagnostics(err, publicUplinkRef.Path)
 		}
 
 		input.UplinkModels.Public = &scgo.DedicatedServerPublicUplinkInput{}
 		input.UplinkModels.Public.ID = publicUplink.ID
 	}
 
-	if bandwidthName, ok := d.GetOk("bandwidth"); ok && publicUplink != nil {
-		bandwidth, err = getBandwidth(location.ID, serverModel.ID, publicUplink.ID, bandwidthName.(string))
+	if bandwidthRef := getOptionRef(d, "bandwidth", "bandwidth_id"); !bandwidthRef.IsEmpty() && publicUplink != nil {
+		bandwidth, err = getBandwidth(ctx, cache, location.ID, serverModel.ID, publicUplink.ID, bandwidthRef)
 		if err != nil {
-			return err
+			return optionDiagnostics(err, bandwidthRef.Path)
 		}
 
 		input.UplinkModels.Public.BandwidthModelID = bandwidth.ID
-	} else if !ok && publicUplink != nil {
-		return fmt.Errorf("bandwidth must be specified, when public uplink is present")
+	} else if bandwidthRef.IsEmpty() && publicUplink != nil {
+		return diag.Errorf("bandwidth must be specified, when public uplink is present")
+	} else if !bandwidthRef.IsEmpty() {
+		return diag.Errorf("public_uplink must be specified, when bandwidth is present")
 	}
 
-	privateUplink, err = getUplink(location.ID, serverModel.ID, d.Get("private_uplink").(string))
+	privateUplinkRef := getOptionRef(d, "private_uplink", "private_uplink_id")
+	privateUplink, err = getUplink(ctx, cache, location.ID, serverModel.ID, privateUplinkRef)
 	if err != nil {
-		return err
+		return optionDiagnostics(err, privateUplinkRef.Path)
 	}
 
 	input.UplinkModels.Private.ID = privateUplink.ID
 
-	slots, err = getSlots(d, location.ID, serverModel.ID)
+	slots, err = getSlots(ctx, cache, d, location.ID, serverModel.ID)
 	if err != nil {
-		return err
+		return optionDiagnostics(err, cty.GetAttrPath("slot"))
 	}
 
-	// TODO: Populate slots from model when len(slots) is zero
-	err = verifySlots(slots)
-	if err != nil {
-		return err
-	}
+	slotsList := d.Get("slot").([]interface{})
+	if len(slots) == 0 {
+		slots, err = getDefaultSlots(ctx, cache, location.ID, serverModel.ID)
+		if err != nil {
+			return optionDiagnostics(err, cty.GetAttrPath("slot"))
+		}
 
-	input.Drives.Slots = slots
+		slotsList = flattenSlots(slots)
+	}
 
 	layouts = getLayouts(d)
 
+	// the layout is used by the operating system installation only
+	layoutsList := d.Get("layout").([]interface{})
+	if len(layouts) == 0 && input.OperatingSystemID != nil {
+		layouts = defaultLayouts(slots)
+		layoutsList = flattenLayouts(layouts)
+	}
+
+	err = verifyDrives(slotsList, layoutsList)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	input.Drives.Slots = slots
 	input.Drives.Layout = layouts
 
 	if val, ok := d.GetOk("ssh_key_fingerprints"); ok {
@@ -475,34 +712,30 @@ func resourceServerscomDedicatedServerCreate(d *schema.ResourceData, meta interf
 		input.UserData = &userDataValue
 	}
 
-	ctx := context.TODO()
-
-	resultChan, err := serverCollector.AddRequest(ctx, "dedicated", input)
+	id, err := orderServer(ctx, meta, "dedicated", input)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	// waiting for result from collector
-	result := <-resultChan
-	if result.Error != nil {
-		return result.Error
-	}
+	// the configured features and power state are applied once the server is active
+	features := expandDedicatedServerFeatures(d.Get("features"))
+	powerState := d.Get("power_state").(string)
+	d.SetId(id)
 
-	if result.Servers.Count() == 0 {
-		return fmt.Errorf("Invalid dedicated servers count returned by api")
-	}
+	d.Set("slot", slotsList)
+	d.Set("layout", layoutsList)
 
-	// find corresponding server by title matching hostname
-	id := result.Servers.GetIdByHostname(hostname)
-	if id == "" {
-		return fmt.Errorf("Can't find the server with title '%s' in api response", hostname)
+	_, err = waitForDedicatedServerAttribute(ctx, d, "active", []string{"init", "pending"}, "status", meta, schema.TimeoutCreate)
+	if err != nil {
+		return diag.Errorf("Error waiting for dedicated server (%s) to become ready: %s", d.Id(), err)
 	}
 
-	d.SetId(id)
+	if err := applyDedicatedServerFeatures(ctx, d, meta, expandDedicatedServerFeatures(nil), features, schema.TimeoutCreate); err != nil {
+		return diag.FromErr(err)
+	}
 
-	_, err = waitForDedicatedServerAttribute(ctx, d, "active", []string{"init", "pending"}, "status", meta, schema.TimeoutCreate)
-	if err != nil {
-		return fmt.Errorf("Error waiting for dedicated server (%s) to become ready: %s", d.Id(), err)
+	if err := applyPowerState(ctx, d, dedicatedServerPower(meta.(*ProviderMeta).Client), powerState, schema.TimeoutCreate); err != nil {
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -518,6 +751,7 @@ func getDriveSlots(slots []scgo.HostDriveSlot) []map[string]interface{} {
 
 		if slot.DriveModel != nil {
 			currentSlot["drive_model"] = slot.DriveModel.Name
+			currentSlot["drive_model_id"] = int(slot.DriveModel.ID)
 		} else {
 			continue
 		}
@@ -528,111 +762,134 @@ func getDriveSlots(slots []scgo.HostDriveSlot) []map[string]interface{} {
 	return driveSlots
 }
 
-func getLocation(code string) (*scgo.Location, error) {
-	locations, err := cache.Locations()
+func getLocation(ctx context.Context, cache *Cache, ref optionRef) (*scgo.Location, error) {
+	locations, err := cache.Locations(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, loc := range locations {
-		if normalizeString(loc.Code) == normalizeString(code) {
+		id := strconv.FormatInt(loc.ID, 10)
+		valid = append(valid, ref.label(id, loc.Code))
+
+		if ref.matches(id, loc.Code) {
 			return &loc, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find location by: %s", code)
+	return nil, ref.notFound("location", valid)
 }
 
-func getServerModel(locationID int64, name string) (*scgo.ServerModelOption, error) {
-	serverModels, err := cache.ServerModels(locationID)
+func getServerModel(ctx context.Context, cache *Cache, locationID int64, ref optionRef) (*scgo.ServerModelOption, error) {
+	serverModels, err := cache.ServerModels(ctx, locationID)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, sm := range serverModels {
-		if normalizeString(sm.Name) == normalizeString(name) {
+		id := strconv.FormatInt(sm.ID, 10)
+		valid = append(valid, ref.label(id, sm.Name))
+
+		if ref.matches(id, sm.Name) {
 			return &sm, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find server model by: %s", name)
+	return nil, ref.notFound("server model", valid)
 }
 
-func getDriveModel(locationID int64, serverModelID int64, name string) (*scgo.DriveModel, error) {
-	driveModels, err := cache.DriveModels(locationID, serverModelID)
+func getDriveModel(ctx context.Context, cache *Cache, locationID int64, serverModelID int64, ref optionRef) (*scgo.DriveModel, error) {
+	driveModels, err := cache.DriveModels(ctx, locationID, serverModelID)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, dm := range driveModels {
-		if normalizeString(dm.Name) == normalizeString(name) {
+		id := strconv.FormatInt(dm.ID, 10)
+		valid = append(valid, ref.label(id, dm.Name))
+
+		if ref.matches(id, dm.Name) {
 			return &dm, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find drive model by: %s", name)
+	return nil, ref.notFound("drive model", valid)
 }
 
-func getOperatingSystem(locationID int64, serverModelID int64, name string) (*scgo.OperatingSystemOption, error) {
-	operatingSystems, err := cache.OperatingSystems(locationID, serverModelID)
+func getOperatingSystem(ctx context.Context, cache *Cache, locationID int64, serverModelID int64, ref optionRef) (*scgo.OperatingSystemOption, error) {
+	operatingSystems, err := cache.OperatingSystems(ctx, locationID, serverModelID)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, os := range operatingSystems {
 		fullName := fmt.Sprintf("%s %s %s", os.Name, os.Version, os.Arch)
+		id := strconv.FormatInt(os.ID, 10)
+		valid = append(valid, ref.label(id, fullName))
 
-		if normalizeString(fullName) == normalizeString(name) {
+		if ref.matches(id, fullName) {
 			return &os, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find operating system by: %s", name)
+	return nil, ref.notFound("operating system", valid)
 }
 
-func getUplink(locationID int64, serverModelID int64, name string) (*scgo.UplinkOption, error) {
-	uplinks, err := cache.Uplinks(locationID, serverModelID)
+func getUplink(ctx context.Context, cache *Cache, locationID int64, serverModelID int64, ref optionRef) (*scgo.UplinkOption, error) {
+	uplinks, err := cache.Uplinks(ctx, locationID, serverModelID)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, uplink := range uplinks {
-		if normalizeString(name) == normalizeString(uplink.Name) {
+		id := strconv.FormatInt(uplink.ID, 10)
+		valid = append(valid, ref.label(id, uplink.Name))
+
+		if ref.matches(id, uplink.Name) {
 			return &uplink, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find uplink by: %s", name)
+	return nil, ref.notFound("uplink", valid)
 }
 
-func getBandwidth(locationID int64, serverModelID int64, uplinkModelID int64, name string) (*scgo.BandwidthOption, error) {
-	bandwidthList, err := cache.Bandwidth(locationID, serverModelID, uplinkModelID)
+func getBandwidth(ctx context.Context, cache *Cache, locationID int64, serverModelID int64, uplinkModelID int64, ref optionRef) (*scgo.BandwidthOption, error) {
+	bandwidthList, err := cache.Bandwidth(ctx, locationID, serverModelID, uplinkModelID)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, bandwidth := range bandwidthList {
-		if normalizeString(name) == normalizeString(bandwidth.Name) {
+		id := strconv.FormatInt(bandwidth.ID, 10)
+		valid = append(valid, ref.label(id, bandwidth.Name))
+
+		if ref.matches(id, bandwidth.Name) {
 			return &bandwidth, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find bandwidth by: %s", name)
+	return nil, ref.notFound("bandwidth", valid)
 }
 
-func getSlots(d *schema.ResourceData, locationID int64, serverModelID int64) ([]scgo.DedicatedServerSlotInput, error) {
+func getSlots(ctx context.Context, cache *Cache, d resourceGetter, locationID int64, serverModelID int64) ([]scgo.DedicatedServerSlotInput, error) {
 	var slotsInput []scgo.DedicatedServerSlotInput
 
 	if slotsList, ok := d.GetOk("slot"); ok {
-		for _, slotSchema := range slotsList.([]interface{}) {
+		for i, slotSchema := range slotsList.([]interface{}) {
 			slot := slotSchema.(map[string]interface{})
 
 			var driveModelID *int64
 
-			if value, ok := slot["drive_model"]; ok && len(value.(string)) != 0 {
-				driveModel, err := getDriveModel(locationID, serverModelID, value.(string))
+			if driveModelRef := getSlotDriveModelRef(d, i, slot); !driveModelRef.IsEmpty() {
+				driveModel, err := getDriveModel(ctx, cache, locationID, serverModelID, driveModelRef)
 				if err != nil {
-					return nil, err
+					return nil, withAttributePath(err, driveModelRef.Path)
 				}
 
 				driveModelID = &driveModel.ID
@@ -652,24 +909,6 @@ func getSlots(d *schema.ResourceData, locationID int64, serverModelID int64) ([]
 	return slotsInput, nil
 }
 
-func verifySlots(slots []scgo.DedicatedServerSlotInput) error {
-	var hasDriveInFirstSlot bool
-
-	for _, slot := range slots {
-		if slot.Position == 0 && slot.DriveModelID != nil {
-			hasDriveInFirstSlot = true
-		}
-	}
-
-	if len(slots) == 0 {
-		return fmt.Errorf("at least one slot must be specified")
-	} else if !hasDriveInFirstSlot {
-		return fmt.Errorf("slot with position 0 must be filled")
-	} else {
-		return nil
-	}
-}
-
 func getLayouts(d *schema.ResourceData) []scgo.DedicatedServerLayoutInput {
 	var layoutInput []scgo.DedicatedServerLayoutInput
 
@@ -719,14 +958,6 @@ func getLayouts(d *schema.ResourceData) []scgo.DedicatedServerLayoutInput {
 	return layoutInput
 }
 
-func verifyLayouts(layouts []scgo.DedicatedServerLayoutInput) error {
-	if len(layouts) == 0 {
-		return fmt.Errorf("at least one layout must be specified")
-	}
-
-	return nil
-}
-
 func waitForDedicatedServerAttribute(ctx context.Context, d *schema.ResourceData, target string, pending []string, attribute string, meta interface{}, timeoutKey string) (interface{}, error) {
 	log.Printf(
 		"[INFO] Waiting for dedicated server (%s) to have %s of %s",
@@ -736,20 +967,20 @@ func waitForDedicatedServerAttribute(ctx context.Context, d *schema.ResourceData
 	stateConf := &retry.StateChangeConf{
 		Pending:      pending,
 		Target:       []string{target},
-		Refresh:      newDedicatedServerStateRefreshFunc(d, attribute, meta),
+		Refresh:      newDedicatedServerStateRefreshFunc(ctx, d, attribute, meta),
 		Timeout:      d.Timeout(timeoutKey),
-		PollInterval: 1 * time.Minute,
-		Delay:        1 * time.Minute,
+		PollInterval: waiterDelay(1 * time.Minute),
+		Delay:        waiterDelay(1 * time.Minute),
 	}
 
 	return stateConf.WaitForStateContext(ctx)
 }
 
-func newDedicatedServerStateRefreshFunc(d *schema.ResourceData, attribute string, meta interface{}) retry.StateRefreshFunc {
+func newDedicatedServerStateRefreshFunc(ctx context.Context, d *schema.ResourceData, attribute string, meta interface{}) retry.StateRefreshFunc {
 	return func() (interface{}, string, error) {
-		err := resourceServerscomDedicatedServerRead(d, meta)
-		if err != nil {
-			return nil, "", err
+		diags := resourceServerscomDedicatedServerRead(ctx, d, meta)
+		if diags.HasError() {
+			return nil, "", errors.New(diags[0].Summary)
 		}
 
 		// See if we can access our attribute
@@ -0,0 +1,309 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+const (
+	serverscomDedicatedServerNetworkDefaultTimeout = 30 * time.Minute
+)
+
+// dedicatedServerNetworkTypes maps network types to their family and interface type
+var dedicatedServerNetworkTypes = map[string]struct {
+	family        string
+	interfaceType string
+}{
+	"public_ipv4":  {"ipv4", "public"},
+	"private_ipv4": {"ipv4", "private"},
+	"public_ipv6":  {"ipv6", "public"},
+}
+
+func resourceServerscomDedicatedServerNetwork() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceServerscomDedicatedServerNetworkRead,
+		DeleteContext: resourceServerscomDedicatedServerNetworkDelete,
+		CreateContext: resourceServerscomDedicatedServerNetworkCreate,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerscomDedicatedServerNetworkImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(serverscomDedicatedServerNetworkDefaultTimeout),
+			Delete: schema.DefaultTimeout(serverscomDedicatedServerNetworkDefaultTimeout),
+		},
+
+		CustomizeDiff: customizeDiffDedicatedServerNetwork,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"public_ipv4", "private_ipv4", "public_ipv6"}, false),
+			},
+			"mask": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 32),
+			},
+			"distribution_method": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"gateway", "route"}, false),
+			},
+			"title": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cidr": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"gateway": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"family": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"interface_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// customizeDiffDedicatedServerNetwork requires the mask for IPv4 networks, IPv6 networks have a fixed size
+func customizeDiffDedicatedServerNetwork(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" {
+		return nil
+	}
+
+	networkType := d.Get("type").(string)
+	maskConfigured := isConfigured(d, cty.GetAttrPath("mask"))
+
+	switch {
+	case networkType == "public_ipv6" && maskConfigured:
+		return fmt.Errorf("mask can't be set for public_ipv6 networks")
+	case networkType != "public_ipv6" && !maskConfigured:
+		return fmt.Errorf("mask is required for %s networks", networkType)
+	}
+
+	return nil
+}
+
+func resourceServerscomDedicatedServerNetworkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
+
+	serverID := d.Get("server_id").(string)
+
+	network, err := client.Hosts.GetDedicatedServerNetwork(ctx, serverID, d.Id())
+	if err != nil {
+		switch err.(type) {
+		case *scgo.NotFoundError:
+			log.Printf("[WARN] Serverscom network (%s) of dedicated server (%s) not found", d.Id(), serverID)
+			d.SetId("")
+			return nil
+		default:
+			return diag.Errorf("Error retrieving network of dedicated server (%s): %s", serverID, err)
+		}
+	}
+
+	for networkType, v := range dedicatedServerNetworkTypes {
+		if v.family == network.Family && v.interfaceType == network.InterfaceType {
+			d.Set("type", networkType)
+		}
+	}
+
+	if network.Cidr != nil && network.Family == "ipv4" {
+		if _, ipv4Net, err := net.ParseCIDR(*network.Cidr); err == nil {
+			mask, _ := ipv4Net.Mask.Size()
+			d.Set("mask", mask)
+		}
+	}
+
+	d.Set("title", network.Title)
+	d.Set("cidr", network.Cidr)
+	d.Set("gateway", network.Gateway)
+	d.Set("family", network.Family)
+	d.Set("interface_type", network.InterfaceType)
+	d.Set("distribution_method", network.DistributionMethod)
+	d.Set("status", network.Status)
+
+	return nil
+}
+
+func resourceServerscomDedicatedServerNetworkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
+
+	serverID := d.Get("server_id").(string)
+
+	input := scgo.NetworkInput{
+		Mask:               d.Get("mask").(int),
+		DistributionMethod: d.Get("distribution_method").(string),
+	}
+
+	if input.DistributionMethod == "" {
+		input.DistributionMethod = "gateway"
+	}
+
+	var network *scgo.Network
+	var err error
+
+	switch networkType := d.Get("type").(string); networkType {
+	case "public_ipv4":
+		network, err = client.Hosts.AddDedicatedServerPublicIPv4Network(ctx, serverID, input)
+	case "private_ipv4":
+		network, err = client.Hosts.AddDedicatedServerPrivateIPv4Network(ctx, serverID, input)
+	case "public_ipv6":
+		network, err = client.Hosts.ActivateDedicatedServerPublicIPv6Network(ctx, serverID)
+	default:
+		return diag.Errorf("Unknown network type: %s", networkType)
+	}
+
+	if err != nil {
+		return diag.FromErr(describeAPIError(err))
+	}
+
+	d.SetId(network.ID)
+
+	_, err = waitForDedicatedServerNetworkStatus(ctx, d, []string{"active"}, []string{"new", "pending"}, meta, schema.TimeoutCreate)
+	if err != nil {
+		return diag.Errorf("Error waiting for network (%s) of dedicated server (%s) to become active: %s", d.Id(), serverID, err)
+	}
+
+	return resourceServerscomDedicatedServerNetworkRead(ctx, d, meta)
+}
+
+func resourceServerscomDedicatedServerNetworkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
+
+	serverID := d.Get("server_id").(string)
+
+	if _, err := client.Hosts.DeleteDedicatedServerNetwork(ctx, serverID, d.Id()); err != nil {
+		switch err.(type) {
+		case *scgo.NotFoundError:
+			log.Printf("[WARN] Serverscom network (%s) of dedicated server (%s) not found", d.Id(), serverID)
+			d.SetId("")
+			return nil
+		default:
+			return diag.FromErr(err)
+		}
+	}
+
+	_, err := waitForDedicatedServerNetworkStatus(ctx, d, []string{"deleted"}, []string{"active", "removing"}, meta, schema.TimeoutDelete)
+	if err != nil {
+		return diag.Errorf("Error waiting for network (%s) of dedicated server (%s) to be removed: %s", d.Id(), serverID, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// resourceServerscomDedicatedServerNetworkImport imports the network by <server_id>/<network_id>
+func resourceServerscomDedicatedServerNetworkImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	serverID, networkID, ok := strings.Cut(d.Id(), "/")
+	if !ok || serverID == "" || networkID == "" {
+		return nil, fmt.Errorf("invalid import id %q, expected <server_id>/<network_id>", d.Id())
+	}
+
+	network, err := meta.(*ProviderMeta).Client.Hosts.GetDedicatedServerNetwork(ctx, serverID, networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !network.Additional {
+		return nil, fmt.Errorf("network (%s) is assigned with the dedicated server (%s) and can't be managed separately", networkID, serverID)
+	}
+
+	d.Set("server_id", serverID)
+	d.SetId(networkID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func waitForDedicatedServerNetworkStatus(ctx context.Context, d *schema.ResourceData, target, pending []string, meta interface{}, timeoutKey string) (interface{}, error) {
+	log.Printf(
+		"[INFO] Waiting for network (%s) of dedicated server (%s) to have status of %s",
+		d.Id(), d.Get("server_id"), strings.Join(target, ", "),
+	)
+
+	stateConf := &retry.StateChangeConf{
+		Pending:      pending,
+		Target:       target,
+		Refresh:      newDedicatedServerNetworkStatusRefreshFunc(ctx, d, meta),
+		Timeout:      d.Timeout(timeoutKey),
+		PollInterval: waiterDelay(15 * time.Second),
+		Delay:        waiterDelay(15 * time.Second),
+	}
+
+	return stateConf.WaitForStateContext(ctx)
+}
+
+// newDedicatedServerNetworkStatusRefreshFunc reports the status of the network, deleted once it's gone
+func newDedicatedServerNetworkStatusRefreshFunc(ctx context.Context, d *schema.ResourceData, meta interface{}) retry.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		network, err := meta.(*ProviderMeta).Client.Hosts.GetDedicatedServerNetwork(ctx, d.Get("server_id").(string), d.Id())
+		if err != nil {
+			if _, ok := err.(*scgo.NotFoundError); ok {
+				return d, "deleted", nil
+			}
+
+			return nil, "", err
+		}
+
+		return network, network.Status, nil
+	}
+}
+
+// getDedicatedServerNetworks converts networks of the dedicated server to the networks attribute value
+func getDedicatedServerNetworks(networks []scgo.Network) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(networks))
+
+	for _, network := range networks {
+		result = append(result, map[string]interface{}{
+			"id":                  network.ID,
+			"title":               stringValue(network.Title),
+			"status":              network.Status,
+			"cidr":                stringValue(network.Cidr),
+			"gateway":             stringValue(network.Gateway),
+			"family":              network.Family,
+			"interface_type":      network.InterfaceType,
+			"distribution_method": network.DistributionMethod,
+			"additional":          network.Additional,
+		})
+	}
+
+	return result
+}
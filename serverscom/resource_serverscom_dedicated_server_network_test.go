@@ -0,0 +1,26 @@
+package serverscom
+
+import (
+	"testing"
+
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func TestGetDedicatedServerNetworks(t *testing.T) {
+	cidr := "203.0.113.0/29"
+
+	networks := getDedicatedServerNetworks([]scgo.Network{
+		{ID: "1", Status: "active", Cidr: &cidr, Family: "ipv4", InterfaceType: "public", DistributionMethod: "route", Additional: true},
+		{ID: "2", Status: "pending", Family: "ipv6", InterfaceType: "public"},
+	})
+
+	if len(networks) != 2 {
+		t.Fatalf("expected 2 networks, got %d", len(networks))
+	}
+	if networks[0]["cidr"] != cidr || networks[0]["additional"] != true {
+		t.Fatalf("unexpected network: %v", networks[0])
+	}
+	if networks[1]["cidr"] != "" || networks[1]["gateway"] != "" || networks[1]["title"] != "" {
+		t.Fatalf("expected unknown values of a pending network to be empty, got %v", networks[1])
+	}
+}
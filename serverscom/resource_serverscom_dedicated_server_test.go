@@ -120,7 +120,7 @@ func testAccServerscomCheckDedicatedServerExists(n string, dedicatedServer *scgo
 			return fmt.Errorf("No dedicated server ID is set")
 		}
 
-		client := testAccProvider.Meta().(*scgo.Client)
+		client := testAccProvider.Meta().(*ProviderMeta).Client
 
 		currentDedicatedServer, err := client.Hosts.GetDedicatedServer(context.Background(), rs.Primary.ID)
 		if err != nil {
@@ -133,7 +133,7 @@ func testAccServerscomCheckDedicatedServerExists(n string, dedicatedServer *scgo
 }
 
 func testAccServerscomCheckDedicatedServerDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*scgo.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "serverscom_dedicated_server" {
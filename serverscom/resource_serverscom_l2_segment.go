@@ -2,11 +2,15 @@ package serverscom
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -21,10 +25,10 @@ var (
 
 func resourceServerscomL2Segment() *schema.Resource {
 	return &schema.Resource{
-		Read:   resourceServerscomL2SegmentRead,
-		Update: resourceServerscomL2SegmentUpdate,
-		Delete: resourceServerscomL2SegmentDelete,
-		Create: resourceServerscomL2SegmentCreate,
+		ReadContext:   resourceServerscomL2SegmentRead,
+		UpdateContext: resourceServerscomL2SegmentUpdate,
+		DeleteContext: resourceServerscomL2SegmentDelete,
+		CreateContext: resourceServerscomL2SegmentCreate,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -35,6 +39,11 @@ func resourceServerscomL2Segment() *schema.Resource {
 			Update: schema.DefaultTimeout(serverscomL2SegmentDefaultUpdateTimeout),
 		},
 
+		CustomizeDiff: customdiff.All(
+			customizeDiffLabelsAll,
+			customizeDiffDeletionProtection("L2 segment", resourceServerscomL2Segment),
+		),
+
 		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
@@ -107,14 +116,14 @@ func resourceServerscomL2Segment() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"labels_all":          labelsAllSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 		},
 	}
 }
 
-func resourceServerscomL2SegmentRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-
-	ctx := context.TODO()
+func resourceServerscomL2SegmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	l2Segment, err := client.L2Segments.Get(ctx, d.Id())
 	if err != nil {
@@ -124,7 +133,7 @@ func resourceServerscomL2SegmentRead(d *schema.ResourceData, meta interface{}) e
 			d.SetId("")
 			return nil
 		default:
-			return fmt.Errorf("Error retrieving l2 segment: %s", err)
+			return diag.Errorf("Error retrieving l2 segment: %s", err)
 		}
 	}
 
@@ -140,7 +149,10 @@ func resourceServerscomL2SegmentRead(d *schema.ResourceData, meta interface{}) e
 	d.Set("status", l2Segment.Status)
 	d.Set("created_at", l2Segment.Created.String())
 	d.Set("updated_at", l2Segment.Updated.String())
-	d.Set("labels", l2Segment.Labels)
+
+	if err := setLabels(d, l2Segment.Labels, meta); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if l2Segment.Status != "active" {
 		return nil
@@ -148,7 +160,7 @@ func resourceServerscomL2SegmentRead(d *schema.ResourceData, meta interface{}) e
 
 	members, err := client.L2Segments.Members(d.Id()).Collect(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	l2Members := getMembers(members)
@@ -158,7 +170,7 @@ func resourceServerscomL2SegmentRead(d *schema.ResourceData, meta interface{}) e
 	return nil
 }
 
-func resourceServerscomL2SegmentUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceServerscomL2SegmentUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	input := scgo.L2SegmentUpdateInput{}
 
 	if d.HasChange("name") {
@@ -170,44 +182,37 @@ func resourceServerscomL2SegmentUpdate(d *schema.ResourceData, meta interface{})
 		input.Members = getSchemaMembers(d)
 	}
 
-	if d.HasChange("labels") {
-		if labelsRaw, ok := d.GetOk("labels"); ok {
-			labels := labelsRaw.(map[string]interface{})
-			stringLabels := make(map[string]string)
-			for k, v := range labels {
-				stringLabels[k] = v.(string)
-			}
-			input.Labels = stringLabels
-		}
+	if d.HasChanges("labels", "labels_all") {
+		input.Labels = expandLabels(d, meta)
 	}
 
-	if d.HasChanges("name", "member") {
-		client := meta.(*scgo.Client)
-
-		ctx := context.TODO()
+	if d.HasChanges("name", "member", "labels", "labels_all") {
+		client := meta.(*ProviderMeta).Client
 
 		if _, err := waitForL2SegmentAttribute(ctx, d, "active", []string{"pending"}, "status", meta, schema.TimeoutUpdate); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		if _, err := client.L2Segments.Update(ctx, d.Id(), input); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		if _, err := waitForL2SegmentAttribute(ctx, d, "active", []string{"pending"}, "status", meta, schema.TimeoutUpdate); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		return resourceServerscomL2SegmentRead(d, meta)
+		return resourceServerscomL2SegmentRead(ctx, d, meta)
 	}
 
 	return nil
 }
 
-func resourceServerscomL2SegmentDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
+func resourceServerscomL2SegmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := checkDeletionProtection(d, "L2 segment"); err != nil {
+		return diag.FromErr(err)
+	}
 
-	ctx := context.TODO()
+	client := meta.(*ProviderMeta).Client
 
 	l2Segment, err := client.L2Segments.Get(ctx, d.Id())
 	if err != nil {
@@ -217,7 +222,7 @@ func resourceServerscomL2SegmentDelete(d *schema.ResourceData, meta interface{})
 			d.SetId("")
 			return nil
 		default:
-			return fmt.Errorf("Error retrieving l2 segment: %s", err)
+			return diag.Errorf("Error retrieving l2 segment: %s", err)
 		}
 	}
 
@@ -229,17 +234,16 @@ func resourceServerscomL2SegmentDelete(d *schema.ResourceData, meta interface{})
 
 	if l2Segment.Status == "pending" {
 		if _, err := waitForL2SegmentAttribute(ctx, d, "active", []string{"pending"}, "status", meta, schema.TimeoutDelete); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
-	return client.L2Segments.Delete(ctx, d.Id())
+	return diag.FromErr(client.L2Segments.Delete(ctx, d.Id()))
 }
 
-func resourceServerscomL2SegmentCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-
-	ctx := context.TODO()
+func resourceServerscomL2SegmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
+	cache := meta.(*ProviderMeta).Cache
 
 	name := d.Get("name").(string)
 
@@ -247,32 +251,25 @@ func resourceServerscomL2SegmentCreate(d *schema.ResourceData, meta interface{})
 	input.Name = &name
 	input.Type = d.Get("type").(string)
 
-	locationGroup, err := getLocationGroup(input.Type, d.Get("location_group").(string))
+	locationGroup, err := getLocationGroup(ctx, cache, input.Type, d.Get("location_group").(string))
 	if err != nil {
-		return err
+		return optionDiagnostics(err, cty.GetAttrPath("location_group"))
 	}
 
 	input.LocationGroupID = locationGroup.ID
 	input.Members = getSchemaMembers(d)
 
-	if labelsRaw, ok := d.GetOk("labels"); ok {
-		labels := labelsRaw.(map[string]interface{})
-		stringLabels := make(map[string]string)
-		for k, v := range labels {
-			stringLabels[k] = v.(string)
-		}
-		input.Labels = stringLabels
-	}
+	input.Labels = expandLabels(d, meta)
 
 	l2Segment, err := client.L2Segments.Create(ctx, input)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(l2Segment.ID)
 
 	if _, err := waitForL2SegmentAttribute(ctx, d, "active", []string{"pending"}, "status", meta, schema.TimeoutCreate); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -306,20 +303,20 @@ func waitForL2SegmentAttribute(ctx context.Context, d *schema.ResourceData, targ
 	stateConf := &retry.StateChangeConf{
 		Pending:    pending,
 		Target:     []string{target},
-		Refresh:    newL2SegmentStateRefreshFunc(d, attribute, meta),
+		Refresh:    newL2SegmentStateRefreshFunc(ctx, d, attribute, meta),
 		Timeout:    d.Timeout(timeoutKey),
-		Delay:      1 * time.Minute,
-		MinTimeout: 15 * time.Second,
+		Delay:      waiterDelay(1 * time.Minute),
+		MinTimeout: waiterDelay(15 * time.Second),
 	}
 
 	return stateConf.WaitForStateContext(ctx)
 }
 
-func newL2SegmentStateRefreshFunc(d *schema.ResourceData, attribute string, meta interface{}) retry.StateRefreshFunc {
+func newL2SegmentStateRefreshFunc(ctx context.Context, d *schema.ResourceData, attribute string, meta interface{}) retry.StateRefreshFunc {
 	return func() (interface{}, string, error) {
-		err := resourceServerscomL2SegmentRead(d, meta)
-		if err != nil {
-			return nil, "", err
+		diags := resourceServerscomL2SegmentRead(ctx, d, meta)
+		if diags.HasError() {
+			return nil, "", errors.New(diags[0].Summary)
 		}
 
 		// See if we can access our attribute
@@ -336,19 +333,26 @@ func newL2SegmentStateRefreshFunc(d *schema.ResourceData, attribute string, meta
 	}
 }
 
-func getLocationGroup(groupType string, groupCode string) (*scgo.L2LocationGroup, error) {
-	locationGroups, err := cache.LocationGroups()
+func getLocationGroup(ctx context.Context, cache *Cache, groupType string, groupCode string) (*scgo.L2LocationGroup, error) {
+	locationGroups, err := cache.LocationGroups(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, locationGroup := range locationGroups {
-		if locationGroup.GroupType == groupType && normalizeString(locationGroup.Code) == normalizeString(groupCode) {
+		if locationGroup.GroupType != groupType {
+			continue
+		}
+
+		valid = append(valid, locationGroup.Code)
+
+		if normalizeString(locationGroup.Code) == normalizeString(groupCode) {
 			return &locationGroup, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find location group by: %s (%s)", groupCode, groupType)
+	return nil, newOptionNotFoundError(fmt.Sprintf("%s location group", groupType), groupCode, valid)
 }
 
 func getSchemaMembers(d *schema.ResourceData) []scgo.L2SegmentMemberInput {
@@ -0,0 +1,364 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func resourceServerscomPTRRecord() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceServerscomPTRRecordRead,
+		DeleteContext: resourceServerscomPTRRecordDelete,
+		CreateContext: resourceServerscomPTRRecordCreate,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerscomPTRRecordImport,
+		},
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"host_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"host_id", "cloud_instance_id"},
+				RequiredWith: []string{"host_type"},
+			},
+			"host_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"dedicated_server", "sbm_server"}, false),
+				RequiredWith: []string{"host_id"},
+			},
+			"cloud_instance_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				ExactlyOneOf: []string{"host_id", "cloud_instance_id"},
+			},
+			"ip": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.IsIPAddress,
+				DiffSuppressFunc: compareIPAddresses,
+			},
+			"domain": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		},
+	}
+}
+
+// ptrRecords manages PTR records of a single parent, a host or a cloud instance.
+// addresses returns the addresses and the networks in CIDR notation assigned to the parent.
+type ptrRecords struct {
+	name      string
+	list      func(ctx context.Context) ([]scgo.PTRRecord, error)
+	create    func(ctx context.Context, input scgo.PTRRecordCreateInput) (*scgo.PTRRecord, error)
+	delete    func(ctx context.Context, ptrRecordID string) error
+	addresses func(ctx context.Context) ([]string, error)
+}
+
+// getPTRRecords returns PTR records of the parent configured by host_id/host_type or cloud_instance_id
+func getPTRRecords(d *schema.ResourceData, client *scgo.Client) (ptrRecords, error) {
+	if cloudInstanceID := d.Get("cloud_instance_id").(string); cloudInstanceID != "" {
+		return ptrRecords{
+			name: fmt.Sprintf("cloud computing instance (%s)", cloudInstanceID),
+			list: func(ctx context.Context) ([]scgo.PTRRecord, error) {
+				return client.CloudComputingInstances.PTRRecords(cloudInstanceID).Collect(ctx)
+			},
+			create: func(ctx context.Context, input scgo.PTRRecordCreateInput) (*scgo.PTRRecord, error) {
+				return client.CloudComputingInstances.CreatePTRRecord(ctx, cloudInstanceID, input)
+			},
+			delete: func(ctx context.Context, ptrRecordID string) error {
+				return client.CloudComputingInstances.DeletePTRRecord(ctx, cloudInstanceID, ptrRecordID)
+			},
+			addresses: func(ctx context.Context) ([]string, error) {
+				instance, err := client.CloudComputingInstances.Get(ctx, cloudInstanceID)
+				if err != nil {
+					return nil, err
+				}
+				return hostAddresses(instance.PublicIPv4Address, instance.PrivateIPv4Address, instance.LocalIPv4Address, instance.PublicIPv6Address), nil
+			},
+		}, nil
+	}
+
+	hostID := d.Get("host_id").(string)
+
+	switch hostType := d.Get("host_type").(string); hostType {
+	case "dedicated_server":
+		return ptrRecords{
+			name: fmt.Sprintf("dedicated server (%s)", hostID),
+			list: func(ctx context.Context) ([]scgo.PTRRecord, error) {
+				return client.Hosts.DedicatedServerPTRRecords(hostID).Collect(ctx)
+			},
+			create: func(ctx context.Context, input scgo.PTRRecordCreateInput) (*scgo.PTRRecord, error) {
+				return client.Hosts.CreatePTRRecordForDedicatedServer(ctx, hostID, input)
+			},
+			delete: func(ctx context.Context, ptrRecordID string) error {
+				return client.Hosts.DeletePTRRecordForDedicatedServer(ctx, hostID, ptrRecordID)
+			},
+			addresses: func(ctx context.Context) ([]string, error) {
+				server, err := client.Hosts.GetDedicatedServer(ctx, hostID)
+				if err != nil {
+					return nil, err
+				}
+
+				// IPv6 and additional networks aren't reported among the server addresses
+				networks, err := client.Hosts.DedicatedServerNetworks(hostID).Collect(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				addresses := hostAddresses(server.PublicIPv4Address, server.PrivateIPv4Address)
+				for _, network := range networks {
+					addresses = append(addresses, hostAddresses(network.Cidr)...)
+				}
+
+				return addresses, nil
+			},
+		}, nil
+	case "sbm_server":
+		return ptrRecords{
+			name: fmt.Sprintf("SBM server (%s)", hostID),
+			list: func(ctx context.Context) ([]scgo.PTRRecord, error) {
+				return client.Hosts.SBMServerPTRRecords(hostID).Collect(ctx)
+			},
+			create: func(ctx context.Context, input scgo.PTRRecordCreateInput) (*scgo.PTRRecord, error) {
+				return client.Hosts.CreatePTRRecordForSBMServer(ctx, hostID, input)
+			},
+			delete: func(ctx context.Context, ptrRecordID string) error {
+				return client.Hosts.DeletePTRRecordForSBMServer(ctx, hostID, ptrRecordID)
+			},
+			addresses: func(ctx context.Context) ([]string, error) {
+				server, err := client.Hosts.GetSBMServer(ctx, hostID)
+				if err != nil {
+					return nil, err
+				}
+				return hostAddresses(server.PublicIPv4Address, server.PrivateIPv4Address), nil
+			},
+		}, nil
+	default:
+		return ptrRecords{}, fmt.Errorf("Unknown host type: %s", hostType)
+	}
+}
+
+func hostAddresses(addresses ...*string) []string {
+	var result []string
+	for _, address := range addresses {
+		if address != nil && *address != "" {
+			result = append(result, *address)
+		}
+	}
+
+	return result
+}
+
+// compareIPAddresses suppresses the diff between different notations of the same address, e.g. for IPv6
+func compareIPAddresses(k, old, new string, d *schema.ResourceData) bool {
+	oldIP, newIP := net.ParseIP(old), net.ParseIP(new)
+
+	return oldIP != nil && newIP != nil && oldIP.Equal(newIP)
+}
+
+// verifyIPAssigned checks the ip is one of the addresses or belongs to one of the networks assigned to the parent
+func verifyIPAssigned(ip string, addresses []string, name string) error {
+	for _, address := range addresses {
+		if _, network, err := net.ParseCIDR(address); err == nil {
+			if network.Contains(net.ParseIP(ip)) {
+				return nil
+			}
+			continue
+		}
+
+		if compareIPAddresses("", address, ip, nil) {
+			return nil
+		}
+	}
+
+	if len(addresses) == 0 {
+		return fmt.Errorf("ip %s isn't assigned to the %s, it has no addresses", ip, name)
+	}
+
+	return fmt.Errorf("ip %s isn't assigned to the %s, assigned addresses: %s", ip, name, strings.Join(addresses, ", "))
+}
+
+func resourceServerscomPTRRecordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	records, err := getPTRRecords(d, meta.(*ProviderMeta).Client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	list, err := records.list(ctx)
+	if err != nil {
+		switch err.(type) {
+		case *scgo.NotFoundError:
+			log.Printf("[WARN] Serverscom %s of the PTR record (%s) not found", records.name, d.Id())
+			d.SetId("")
+			return nil
+		default:
+			return diag.Errorf("Error retrieving PTR records of the %s: %s", records.name, err)
+		}
+	}
+
+	for _, record := range list {
+		if record.ID != d.Id() {
+			continue
+		}
+
+		d.Set("ip", record.IP)
+		d.Set("domain", record.Domain)
+		d.Set("ttl", record.TTL)
+		d.Set("priority", record.Priority)
+
+		return nil
+	}
+
+	log.Printf("[WARN] Serverscom PTR record (%s) not found", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func resourceServerscomPTRRecordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	records, err := getPTRRecords(d, meta.(*ProviderMeta).Client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ip := d.Get("ip").(string)
+
+	addresses, err := records.addresses(ctx)
+	if err != nil {
+		return diag.Errorf("Error retrieving %s: %s", records.name, err)
+	}
+
+	if err := verifyIPAssigned(ip, addresses, records.name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	input := scgo.PTRRecordCreateInput{
+		IP:     ip,
+		Domain: d.Get("domain").(string),
+	}
+
+	if ttl, ok := d.GetOk("ttl"); ok {
+		ttlValue := ttl.(int)
+		input.TTL = &ttlValue
+	}
+
+	if priority, ok := d.GetOk("priority"); ok {
+		priorityValue := priority.(int)
+		input.Priority = &priorityValue
+	}
+
+	record, err := records.create(ctx, input)
+	if err != nil {
+		return diag.FromErr(describeAPIError(err))
+	}
+
+	d.SetId(record.ID)
+
+	return resourceServerscomPTRRecordRead(ctx, d, meta)
+}
+
+func resourceServerscomPTRRecordDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	records, err := getPTRRecords(d, meta.(*ProviderMeta).Client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := records.delete(ctx, d.Id()); err != nil {
+		switch err.(type) {
+		case *scgo.NotFoundError:
+			log.Printf("[WARN] Serverscom PTR record (%s) not found", d.Id())
+		default:
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// resourceServerscomPTRRecordImport imports the PTR record by <host_id>/<ptr_id>,
+// the host is looked up among dedicated servers, SBM servers and cloud computing instances
+func resourceServerscomPTRRecordImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*ProviderMeta).Client
+
+	hostID, ptrID, ok := strings.Cut(d.Id(), "/")
+	if !ok || hostID == "" || ptrID == "" {
+		return nil, fmt.Errorf("invalid import id %q, expected <host_id>/<ptr_id>", d.Id())
+	}
+
+	parentType, err := findPTRRecordParent(ctx, client, hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentType == "cloud_instance" {
+		d.Set("cloud_instance_id", hostID)
+	} else {
+		d.Set("host_id", hostID)
+		d.Set("host_type", parentType)
+	}
+
+	d.SetId(ptrID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// findPTRRecordParent returns host_type of the host with the id, or cloud_instance for a cloud computing instance
+func findPTRRecordParent(ctx context.Context, client *scgo.Client, id string) (string, error) {
+	lookups := []struct {
+		parentType string
+		get        func() error
+	}{
+		{"dedicated_server", func() error { _, err := client.Hosts.GetDedicatedServer(ctx, id); return err }},
+		{"sbm_server", func() error { _, err := client.Hosts.GetSBMServer(ctx, id); return err }},
+		{"cloud_instance", func() error { _, err := client.CloudComputingInstances.Get(ctx, id); return err }},
+	}
+
+	for _, lookup := range lookups {
+		err := lookup.get()
+		if err == nil {
+			return lookup.parentType, nil
+		}
+
+		if _, ok := err.(*scgo.NotFoundError); !ok {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("Can't find a dedicated server, SBM server or cloud computing instance with id %s", id)
+}
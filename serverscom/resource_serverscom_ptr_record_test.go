@@ -0,0 +1,34 @@
+package serverscom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyIPAssigned(t *testing.T) {
+	addresses := []string{"198.51.100.1", "2001:db8::1"}
+
+	if err := verifyIPAssigned("198.51.100.1", addresses, "dedicated server (1)"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := verifyIPAssigned("2001:0db8:0000::0001", addresses, "dedicated server (1)"); err != nil {
+		t.Fatalf("expected IPv6 notations to match, got %s", err)
+	}
+
+	networks := []string{"198.51.100.1", "198.51.100.1/32", "203.0.113.8/29", "2001:db8:1::/64"}
+
+	if err := verifyIPAssigned("203.0.113.12", networks, "dedicated server (1)"); err != nil {
+		t.Fatalf("expected the alias address of the additional network to be assigned, got %s", err)
+	}
+	if err := verifyIPAssigned("2001:db8:1::10", networks, "dedicated server (1)"); err != nil {
+		t.Fatalf("expected the IPv6 network address to be assigned, got %s", err)
+	}
+	if err := verifyIPAssigned("203.0.113.16", networks, "dedicated server (1)"); err == nil {
+		t.Fatalf("expected the address outside of the networks to be refused")
+	}
+
+	err := verifyIPAssigned("198.51.100.2", addresses, "dedicated server (1)")
+	if err == nil || !strings.Contains(err.Error(), "isn't assigned to the dedicated server (1), assigned addresses: 198.51.100.1, 2001:db8::1") {
+		t.Fatalf("expected not assigned error, got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -33,6 +34,10 @@ func resourceServerscomRBSVolume() *schema.Resource {
 			Create: schema.DefaultTimeout(rbsDefaultCreateTimeout),
 			Delete: schema.DefaultTimeout(rbsDefaultDeleteTimeout),
 		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffLabelsAll,
+			customizeDiffDeletionProtection("RBS volume", resourceServerscomRBSVolume),
+		),
 		SchemaVersion: 1,
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -58,6 +63,8 @@ func resourceServerscomRBSVolume() *schema.Resource {
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"labels_all":          labelsAllSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 
 			"status":        {Type: schema.TypeString, Computed: true},
 			"ip_address":    {Type: schema.TypeString, Computed: true},
@@ -73,7 +80,7 @@ func resourceServerscomRBSVolume() *schema.Resource {
 }
 
 func resourceServerscomRBSVolumeCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
 
 	input := scgo.RemoteBlockStorageVolumeCreateInput{
 		Name:       d.Get("name").(string),
@@ -81,9 +88,7 @@ func resourceServerscomRBSVolumeCreate(ctx context.Context, d *schema.ResourceDa
 		LocationID: d.Get("location_id").(int),
 		FlavorID:   d.Get("flavor_id").(int),
 	}
-	if labelsRaw, ok := d.GetOk("labels"); ok {
-		input.Labels = toStringMap(labelsRaw.(map[string]interface{}))
-	}
+	input.Labels = expandLabels(d, meta)
 
 	vol, err := client.RemoteBlockStorageVolumes.Create(ctx, input)
 	if err != nil {
@@ -105,7 +110,7 @@ func resourceServerscomRBSVolumeCreate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceServerscomRBSVolumeRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
 
 	vol, err := client.RemoteBlockStorageVolumes.Get(ctx, d.Id())
 	if err != nil {
@@ -121,7 +126,10 @@ func resourceServerscomRBSVolumeRead(ctx context.Context, d *schema.ResourceData
 	d.Set("size", int(vol.Size))
 	d.Set("location_id", vol.LocationID)
 	d.Set("flavor_id", vol.FlavorID)
-	d.Set("labels", vol.Labels)
+
+	if err := setLabels(d, vol.Labels, meta); err != nil {
+		return diag.FromErr(err)
+	}
 
 	d.Set("status", vol.Status)
 	if vol.IPAddress != nil {
@@ -145,7 +153,7 @@ func resourceServerscomRBSVolumeRead(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceServerscomRBSVolumeUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	client := meta.(*ProviderMeta).Client
 
 	input := scgo.RemoteBlockStorageVolumeUpdateInput{}
 	changesSize := false
@@ -153,12 +161,8 @@ func resourceServerscomRBSVolumeUpdate(ctx context.Context, d *schema.ResourceDa
 	if d.HasChange("name") {
 		input.Name = d.Get("name").(string)
 	}
-	if d.HasChange("labels") {
-		if labelsRaw, ok := d.GetOk("labels"); ok {
-			input.Labels = toStringMap(labelsRaw.(map[string]interface{}))
-		} else {
-			input.Labels = map[string]string{}
-		}
+	if d.HasChanges("labels", "labels_all") {
+		input.Labels = expandLabels(d, meta)
 	}
 	if d.HasChange("size") {
 		newSize := int64(d.Get("size").(int))
@@ -184,7 +188,11 @@ func resourceServerscomRBSVolumeUpdate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceServerscomRBSVolumeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*scgo.Client)
+	if err := checkDeletionProtection(d, "RBS volume"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*ProviderMeta).Client
 
 	err := client.RemoteBlockStorageVolumes.Delete(ctx, d.Id())
 	if err != nil {
@@ -215,17 +223,15 @@ func toStringMap(m map[string]interface{}) map[string]string {
 }
 
 func waitForRBSVolumeAttribute(ctx context.Context, d *schema.ResourceData, target []string, pending []string, attribute string, meta any, timeoutKey string) (any, error) {
-	client := meta.(*scgo.Client)
-
 	log.Printf("[INFO] Waiting for rbs volume (%s) attribute %s -> %s", d.Id(), attribute, target)
 
 	stateConf := &retry.StateChangeConf{
 		Pending:      pending,
 		Target:       target,
-		Refresh:      newRBSVolumeStateRefreshFunc(ctx, d, attribute, client),
+		Refresh:      newRBSVolumeStateRefreshFunc(ctx, d, attribute, meta),
 		Timeout:      d.Timeout(timeoutKey),
-		PollInterval: 15 * time.Second,
-		Delay:        15 * time.Second,
+		PollInterval: waiterDelay(15 * time.Second),
+		Delay:        waiterDelay(15 * time.Second),
 	}
 
 	return stateConf.WaitForStateContext(ctx)
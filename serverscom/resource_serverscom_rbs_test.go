@@ -230,7 +230,7 @@ func testAccServerscomCheckRBSVolumeExists(n string, rbsVolume *scgo.RemoteBlock
 			return fmt.Errorf("No RBS volume ID is set")
 		}
 
-		client := testAccProvider.Meta().(*scgo.Client)
+		client := testAccProvider.Meta().(*ProviderMeta).Client
 		currentVolume, err := client.RemoteBlockStorageVolumes.Get(context.Background(), rs.Primary.ID)
 		if err != nil {
 			return err
@@ -242,7 +242,7 @@ func testAccServerscomCheckRBSVolumeExists(n string, rbsVolume *scgo.RemoteBlock
 }
 
 func testAccServerscomCheckRBSVolumeDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*scgo.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "serverscom_rbs_volume" {
@@ -2,11 +2,14 @@ package serverscom
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -16,23 +19,31 @@ import (
 var (
 	serverscomSBMDefaultCreateTimeout = 5 * time.Minute
 	serverscomSBMDefaultDeleteTimeout = 1 * time.Minute
+	serverscomSBMDefaultUpdateTimeout = 10 * time.Minute
 )
 
 func resourceServerscomSBM() *schema.Resource {
 	return &schema.Resource{
-		Read:   resourceServerscomSBMRead,
-		Update: resourceServerscomSBMUpdate,
-		Delete: resourceServerscomSBMDelete,
-		Create: resourceServerscomSBMCreate,
+		ReadContext:   resourceServerscomSBMRead,
+		UpdateContext: resourceServerscomSBMUpdate,
+		DeleteContext: resourceServerscomSBMDelete,
+		CreateContext: resourceServerscomSBMCreate,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importByReference("SBM server", hostLookup("sbm_server"), resourceServerscomSBM),
 		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(serverscomSBMDefaultCreateTimeout),
 			Delete: schema.DefaultTimeout(serverscomSBMDefaultDeleteTimeout),
+			Update: schema.DefaultTimeout(serverscomSBMDefaultUpdateTimeout),
 		},
 
+		CustomizeDiff: customdiff.All(
+			customizeDiffLabelsAll,
+			customizeDiffSBMServerOrderOptions,
+			customizeDiffDeletionProtection("SBM server", resourceServerscomSBM),
+		),
+
 		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
@@ -43,23 +54,50 @@ func resourceServerscomSBM() *schema.Resource {
 			},
 			"location": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
 				ForceNew:         true,
 				DiffSuppressFunc: compareStrings,
 				ValidateFunc:     validation.NoZeroValues,
+				ExactlyOneOf:     []string{"location", "location_id"},
+			},
+			"location_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"location", "location_id"},
 			},
 			"flavor": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
 				ForceNew:         true,
 				DiffSuppressFunc: compareStrings,
 				ValidateFunc:     validation.NoZeroValues,
+				ExactlyOneOf:     []string{"flavor", "flavor_id"},
+			},
+			"flavor_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"flavor", "flavor_id"},
 			},
 			"operating_system": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
 				DiffSuppressFunc: compareStrings,
 				ValidateFunc:     validation.NoZeroValues,
+				ExactlyOneOf:     []string{"operating_system", "operating_system_id"},
+			},
+			"operating_system_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"operating_system", "operating_system_id"},
 			},
 			"ssh_key_fingerprints": {
 				Type:     schema.TypeList,
@@ -98,6 +136,7 @@ func resourceServerscomSBM() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"power_state": powerStateSchema(),
 			"labels": {
 				Type:     schema.TypeMap,
 				Optional: true,
@@ -105,14 +144,14 @@ func resourceServerscomSBM() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"labels_all":          labelsAllSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 		},
 	}
 }
 
-func resourceServerscomSBMRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-
-	ctx := context.TODO()
+func resourceServerscomSBMRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	sbm, err := client.Hosts.GetSBMServer(ctx, d.Id())
 	if err != nil {
@@ -122,17 +161,23 @@ func resourceServerscomSBMRead(d *schema.ResourceData, meta interface{}) error {
 			d.SetId("")
 			return nil
 		default:
-			return fmt.Errorf("Error retrieving SBM server: %s", err)
+			return diag.Errorf("Error retrieving SBM server: %s", err)
 		}
 	}
 
 	d.Set("hostname", sbm.Title)
 	d.Set("status", sbm.Status)
+	setPowerState(d, sbm.PowerStatus)
 	d.Set("operating_system", sbm.ConfigurationDetails.OperatingSystemFullName)
+	d.Set("operating_system_id", sbm.ConfigurationDetails.OperatingSystemID)
 	d.Set("location", sbm.LocationCode)
+	d.Set("location_id", sbm.LocationID)
 	d.Set("private_ipv4_address", sbm.PrivateIPv4Address)
 	d.Set("public_ipv4_address", sbm.PublicIPv4Address)
-	d.Set("labels", sbm.Labels)
+
+	if err := setLabels(d, sbm.Labels, meta); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if sbm.Status != "active" {
 		return nil
@@ -141,39 +186,49 @@ func resourceServerscomSBMRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceServerscomSBMUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceServerscomSBMUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
+
 	input := scgo.SBMServerUpdateInput{}
 
 	hasChanges := false
-	if d.HasChange("labels") {
+	if d.HasChanges("labels", "labels_all") {
 		hasChanges = true
-		if labelsRaw, ok := d.GetOk("labels"); ok {
-			labels := labelsRaw.(map[string]interface{})
-			stringLabels := make(map[string]string)
-			for k, v := range labels {
-				stringLabels[k] = v.(string)
-			}
-			input.Labels = stringLabels
+
+		sbm, err := client.Hosts.GetSBMServer(ctx, d.Id())
+		if err != nil {
+			return diag.Errorf("Error retrieving SBM server: %s", err)
 		}
+
+		input.Labels = keepIdempotencyLabel(expandLabels(d, meta), sbm.Labels)
 	}
 
 	if hasChanges {
-		client := meta.(*scgo.Client)
-		ctx := context.TODO()
-
 		if _, err := client.Hosts.UpdateSBMServer(ctx, d.Id(), input); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
+	}
 
-		return resourceServerscomSBMRead(d, meta)
+	if d.HasChange("power_state") {
+		hasChanges = true
+		if err := applyPowerState(ctx, d, sbmServerPower(client), d.Get("power_state").(string), schema.TimeoutUpdate); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if hasChanges {
+		return resourceServerscomSBMRead(ctx, d, meta)
 	}
 
 	return nil
 }
 
-func resourceServerscomSBMDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func resourceServerscomSBMDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := checkDeletionProtection(d, "SBM server"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	client := meta.(*ProviderMeta).Client
 
 	sbm, err := client.Hosts.GetSBMServer(ctx, d.Id())
 	if err != nil {
@@ -183,30 +238,31 @@ func resourceServerscomSBMDelete(d *schema.ResourceData, meta interface{}) error
 			d.SetId("")
 			return nil
 		default:
-			return fmt.Errorf("Error retrieving SBM server: %s", err.Error())
+			return diag.Errorf("Error retrieving SBM server: %s", err.Error())
 		}
 	}
 
 	if sbm.Status == "pending" || sbm.Status == "init" {
 		_, err = waitForSBMAttribute(ctx, d, "active", []string{"init", "pending"}, "status", meta, schema.TimeoutDelete)
 		if err != nil {
-			return fmt.Errorf("Error waiting for SBM server (%s) to become ready: %s", d.Id(), err)
+			return diag.Errorf("Error waiting for SBM server (%s) to become ready: %s", d.Id(), err)
 		}
 	}
 
 	if _, err := client.Hosts.ReleaseSBMServer(ctx, d.Id()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceServerscomSBMCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceServerscomSBMCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var (
 		publicIpv4NetworkId  *string
 		privateIpv4NetworkId *string
 	)
 
+	cache := meta.(*ProviderMeta).Cache
 	input := &SBMServerCreateInput{}
 
 	if id, ok := d.GetOk("public_ipv4_network_id"); ok {
@@ -227,33 +283,28 @@ func resourceServerscomSBMCreate(d *schema.ResourceData, meta interface{}) error
 			PrivateIPv4NetworkID: privateIpv4NetworkId,
 		},
 	}
-	if labelsRaw, ok := d.GetOk("labels"); ok {
-		labels := labelsRaw.(map[string]interface{})
-		stringLabels := make(map[string]string)
-		for k, v := range labels {
-			stringLabels[k] = v.(string)
-		}
-		input.Hosts[0].Labels = stringLabels
-	}
+	input.Hosts[0].Labels = expandLabels(d, meta)
 
-	location, err := getLocation(d.Get("location").(string))
+	locationRef := getOptionRef(d, "location", "location_id")
+	location, err := getLocation(ctx, cache, locationRef)
 	if err != nil {
-		return err
+		return optionDiagnostics(err, locationRef.Path)
 	}
 
 	input.LocationID = location.ID
 
-	flavor, err := getSBMFlavor(location.ID, d.Get("flavor").(string))
+	flavorRef := getOptionRef(d, "flavor", "flavor_id")
+	flavor, err := getSBMFlavor(ctx, cache, location.ID, flavorRef)
 	if err != nil {
-		return err
+		return optionDiagnostics(err, flavorRef.Path)
 	}
 
 	input.FlavorModelID = flavor.ID
 
-	if operatingSystemName, ok := d.GetOk("operating_system"); ok {
-		operatingSystem, err := getSBMOperatingSystem(location.ID, flavor.ID, operatingSystemName.(string))
+	if operatingSystemRef := getOptionRef(d, "operating_system", "operating_system_id"); !operatingSystemRef.IsEmpty() {
+		operatingSystem, err := getSBMOperatingSystem(ctx, cache, location.ID, flavor.ID, operatingSystemRef)
 		if err != nil {
-			return err
+			return optionDiagnostics(err, operatingSystemRef.Path)
 		}
 
 		input.OperatingSystemID = &operatingSystem.ID
@@ -268,34 +319,22 @@ func resourceServerscomSBMCreate(d *schema.ResourceData, meta interface{}) error
 		input.UserData = &userDataValue
 	}
 
-	ctx := context.TODO()
-
-	resultChan, err := serverCollector.AddRequest(ctx, "sbm", input)
+	id, err := orderServer(ctx, meta, "sbm", input)
 	if err != nil {
-		return err
-	}
-
-	// waiting for result from collector
-	result := <-resultChan
-	if result.Error != nil {
-		return result.Error
-	}
-
-	if result.Servers.Count() == 0 {
-		return fmt.Errorf("Invalid SBM servers count returned by api")
-	}
-
-	// find corresponding server by title matching hostname
-	id := result.Servers.GetIdByHostname(hostname)
-	if id == "" {
-		return fmt.Errorf("Can't find the server with title '%s' in api response", hostname)
+		return diag.FromErr(err)
 	}
 
+	// the configured power state is applied once the server is active
+	powerState := d.Get("power_state").(string)
 	d.SetId(id)
 
 	_, err = waitForSBMAttribute(ctx, d, "active", []string{"init", "pending"}, "status", meta, schema.TimeoutCreate)
 	if err != nil {
-		return fmt.Errorf("Error waiting for SBM server (%s) to become ready: %s", d.Id(), err)
+		return diag.Errorf("Error waiting for SBM server (%s) to become ready: %s", d.Id(), err)
+	}
+
+	if err := applyPowerState(ctx, d, sbmServerPower(meta.(*ProviderMeta).Client), powerState, schema.TimeoutCreate); err != nil {
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -310,20 +349,20 @@ func waitForSBMAttribute(ctx context.Context, d *schema.ResourceData, target str
 	stateConf := &retry.StateChangeConf{
 		Pending:    pending,
 		Target:     []string{target},
-		Refresh:    newSBMStateRefreshFunc(d, attribute, meta),
+		Refresh:    newSBMStateRefreshFunc(ctx, d, attribute, meta),
 		Timeout:    d.Timeout(timeoutKey),
-		Delay:      10 * time.Second,
-		MinTimeout: 10 * time.Second,
+		Delay:      waiterDelay(10 * time.Second),
+		MinTimeout: waiterDelay(10 * time.Second),
 	}
 
 	return stateConf.WaitForStateContext(ctx)
 }
 
-func newSBMStateRefreshFunc(d *schema.ResourceData, attribute string, meta interface{}) retry.StateRefreshFunc {
+func newSBMStateRefreshFunc(ctx context.Context, d *schema.ResourceData, attribute string, meta interface{}) retry.StateRefreshFunc {
 	return func() (interface{}, string, error) {
-		err := resourceServerscomSBMRead(d, meta)
-		if err != nil {
-			return nil, "", err
+		diags := resourceServerscomSBMRead(ctx, d, meta)
+		if diags.HasError() {
+			return nil, "", errors.New(diags[0].Summary)
 		}
 
 		// See if we can access our attribute
@@ -340,36 +379,43 @@ func newSBMStateRefreshFunc(d *schema.ResourceData, attribute string, meta inter
 	}
 }
 
-func getSBMOperatingSystem(locationID int64, sbmFlavorModelID int64, name string) (*scgo.OperatingSystemOption, error) {
-	operatingSystems, err := cache.SBMOperatingSystems(locationID, sbmFlavorModelID)
+func getSBMOperatingSystem(ctx context.Context, cache *Cache, locationID int64, sbmFlavorModelID int64, ref optionRef) (*scgo.OperatingSystemOption, error) {
+	operatingSystems, err := cache.SBMOperatingSystems(ctx, locationID, sbmFlavorModelID)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, os := range operatingSystems {
 		fullName := fmt.Sprintf("%s %s %s", os.Name, os.Version, os.Arch)
+		id := strconv.FormatInt(os.ID, 10)
+		valid = append(valid, ref.label(id, fullName))
 
-		if normalizeString(fullName) == normalizeString(name) {
+		if ref.matches(id, fullName) {
 			return &os, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find operating system by: %s", name)
+	return nil, ref.notFound("operating system", valid)
 }
 
-func getSBMFlavor(regionID int64, name string) (*scgo.SBMFlavor, error) {
-	flavors, err := cache.SBMFlavors(regionID)
+func getSBMFlavor(ctx context.Context, cache *Cache, regionID int64, ref optionRef) (*scgo.SBMFlavor, error) {
+	flavors, err := cache.SBMFlavors(ctx, regionID)
 	if err != nil {
 		return nil, err
 	}
 
+	var valid []string
 	for _, flavor := range flavors {
-		if normalizeString(flavor.Name) == normalizeString(name) {
+		id := strconv.FormatInt(flavor.ID, 10)
+		valid = append(valid, ref.label(id, flavor.Name))
+
+		if ref.matches(id, flavor.Name) {
 			return &flavor, nil
 		}
 	}
 
-	return nil, fmt.Errorf("Can't find SBM flavor by: %s", name)
+	return nil, ref.notFound("SBM flavor", valid)
 }
 
 // SBMServerCreateInput implements ServerCreateInput interface
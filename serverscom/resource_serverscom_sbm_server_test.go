@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
@@ -62,10 +64,68 @@ func TestAccServerscomSBMServer_Basic(t *testing.T) {
 						"serverscom_sbm_server.node", "operating_system", "Ubuntu 18.04-server x86_64"),
 				),
 			},
+			{
+				ResourceName:  "serverscom_sbm_server.node",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("hostname=node-%d", rInt),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].ID != sbmServer.ID {
+						return fmt.Errorf("expected SBM server (%s) to be imported by hostname, got %v", sbmServer.ID, states)
+					}
+					return nil
+				},
+			},
 		},
 	})
 }
 
+func TestResourceServerscomSBMUpdate_KeepsIdempotencyLabel(t *testing.T) {
+	ctx := context.Background()
+	client := testFakeAPIClient(t)
+	meta := &ProviderMeta{Client: client}
+
+	id := testCreateSBMServer(t, client, "node-1", map[string]string{idempotencyLabel: "key", "role": "db"})
+
+	d := schema.TestResourceDataRaw(t, resourceServerscomSBM().Schema, map[string]interface{}{
+		"labels": map[string]interface{}{"role": "web"},
+	})
+	d.SetId(id)
+
+	if diags := resourceServerscomSBMUpdate(ctx, d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	sbm, err := client.Hosts.GetSBMServer(ctx, id)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]string{idempotencyLabel: "key", "role": "web"}
+	if !reflect.DeepEqual(sbm.Labels, expected) {
+		t.Fatalf("expected labels %v, got %v", expected, sbm.Labels)
+	}
+
+	if _, ok := d.Get("labels_all").(map[string]interface{})[idempotencyLabel]; ok {
+		t.Fatalf("expected the idempotency label to be left out of labels_all")
+	}
+}
+
+// testCreateSBMServer orders the SBM server from the fake api and returns its id
+func testCreateSBMServer(t *testing.T, client *scgo.Client, hostname string, labels map[string]string) string {
+	t.Helper()
+
+	servers, err := client.Hosts.CreateSBMServers(context.Background(), scgo.SBMServerCreateInput{
+		FlavorModelID: 1,
+		LocationID:    1,
+		Hosts:         []scgo.SBMServerHostInput{{Hostname: hostname, Labels: labels}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return servers[0].ID
+}
+
 func testAccServerscomSBMServerConfig_basic(rInt int) string {
 	return fmt.Sprintf(`
 resource "serverscom_sbm_server" "node" {
@@ -88,7 +148,7 @@ func testAccServerscomCheckSBMServerExists(n string, sbmServer *scgo.SBMServer)
 			return fmt.Errorf("No SBM server ID is set")
 		}
 
-		client := testAccProvider.Meta().(*scgo.Client)
+		client := testAccProvider.Meta().(*ProviderMeta).Client
 
 		currentSBMServer, err := client.Hosts.GetSBMServer(context.Background(), rs.Primary.ID)
 		if err != nil {
@@ -101,7 +161,7 @@ func testAccServerscomCheckSBMServerExists(n string, sbmServer *scgo.SBMServer)
 }
 
 func testAccServerscomCheckSBMServerDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*scgo.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "serverscom_sbm_server" {
@@ -2,10 +2,10 @@ package serverscom
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	scgo "github.com/serverscom/serverscom-go-client/pkg"
@@ -13,14 +13,16 @@ import (
 
 func resourceServerscomSSHKey() *schema.Resource {
 	return &schema.Resource{
-		Read:   resourceServerscomSSHKeyRead,
-		Update: resourceServerscomSSHKeyUpdate,
-		Delete: resourceServerscomSSHKeyDelete,
-		Create: resourceServerscomSSHKeyCreate,
+		ReadContext:   resourceServerscomSSHKeyRead,
+		UpdateContext: resourceServerscomSSHKeyUpdate,
+		DeleteContext: resourceServerscomSSHKeyDelete,
+		CreateContext: resourceServerscomSSHKeyCreate,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: customizeDiffLabelsAll,
+
 		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
@@ -47,32 +49,34 @@ func resourceServerscomSSHKey() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"labels_all": labelsAllSchema(),
 		},
 	}
 }
 
-func resourceServerscomSSHKeyRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func resourceServerscomSSHKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	sshKey, err := client.SSHKeys.Get(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.Set("name", sshKey.Name)
 	d.Set("fingerprint", sshKey.Fingerprint)
-	d.Set("labels", sshKey.Labels)
+
+	if err := setLabels(d, sshKey.Labels, meta); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
 
-func resourceServerscomSSHKeyUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func resourceServerscomSSHKeyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	if _, err := client.SSHKeys.Get(ctx, d.Id()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	var newName string
@@ -83,27 +87,19 @@ func resourceServerscomSSHKeyUpdate(d *schema.ResourceData, meta interface{}) er
 	input := scgo.SSHKeyUpdateInput{}
 	input.Name = newName
 
-	if d.HasChange("labels") {
-		if labelsRaw, ok := d.GetOk("labels"); ok {
-			labels := labelsRaw.(map[string]interface{})
-			stringLabels := make(map[string]string)
-			for k, v := range labels {
-				stringLabels[k] = v.(string)
-			}
-			input.Labels = stringLabels
-		}
+	if d.HasChanges("labels", "labels_all") {
+		input.Labels = expandLabels(d, meta)
 	}
 
 	if _, err := client.SSHKeys.Update(ctx, d.Id(), input); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceServerscomSSHKeyRead(d, meta)
+	return resourceServerscomSSHKeyRead(ctx, d, meta)
 }
 
-func resourceServerscomSSHKeyDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func resourceServerscomSSHKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	err := client.SSHKeys.Delete(ctx, d.Id())
 	if err != nil {
@@ -113,38 +109,30 @@ func resourceServerscomSSHKeyDelete(d *schema.ResourceData, meta interface{}) er
 			d.SetId("")
 			return nil
 		default:
-			return fmt.Errorf("Error retrieving ssh key: %s", err.Error())
+			return diag.Errorf("Error retrieving ssh key: %s", err.Error())
 		}
 	}
 
 	return nil
 }
 
-func resourceServerscomSSHKeyCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*scgo.Client)
-	ctx := context.TODO()
+func resourceServerscomSSHKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*ProviderMeta).Client
 
 	input := scgo.SSHKeyCreateInput{}
 	input.PublicKey = d.Get("public_key").(string)
 	input.Name = d.Get("name").(string)
 
-	if labelsRaw, ok := d.GetOk("labels"); ok {
-		labels := labelsRaw.(map[string]interface{})
-		stringLabels := make(map[string]string)
-		for k, v := range labels {
-			stringLabels[k] = v.(string)
-		}
-		input.Labels = stringLabels
-	}
+	input.Labels = expandLabels(d, meta)
 
 	sshKey, err := client.SSHKeys.Create(ctx, input)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(sshKey.Fingerprint)
 
-	return resourceServerscomSSHKeyRead(d, meta)
+	return resourceServerscomSSHKeyRead(ctx, d, meta)
 }
 
 func resourceServerscomSSHKeyPublicKeyDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
@@ -0,0 +1,283 @@
+package serverscom
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	defaultRetryMaxAttempts = 5
+	defaultRetryMinBackoff  = 1 * time.Second
+	defaultRetryMaxBackoff  = 30 * time.Second
+)
+
+// RetryConfig describes how transient API errors are retried
+type RetryConfig struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryConfig returns the retry config used when the provider has no retry block
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: defaultRetryMaxAttempts,
+		MinBackoff:  defaultRetryMinBackoff,
+		MaxBackoff:  defaultRetryMaxBackoff,
+	}
+}
+
+func retrySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Retry settings for transient API errors",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"max_attempts": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      defaultRetryMaxAttempts,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Maximum number of attempts for a single API request, including the first one.",
+				},
+				"min_backoff": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      defaultRetryMinBackoff.String(),
+					ValidateFunc: validateDuration,
+					Description:  "Backoff before the first retry, doubled on every next retry.",
+				},
+				"max_backoff": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      defaultRetryMaxBackoff.String(),
+					ValidateFunc: validateDuration,
+					Description:  "Upper bound for the backoff between retries.",
+				},
+			},
+		},
+	}
+}
+
+// expandRetryConfig builds RetryConfig from the provider retry block
+func expandRetryConfig(d *schema.ResourceData) (RetryConfig, error) {
+	config := DefaultRetryConfig()
+
+	v, ok := d.GetOk("retry")
+	if !ok || len(v.([]interface{})) == 0 || v.([]interface{})[0] == nil {
+		return config, nil
+	}
+
+	retry := v.([]interface{})[0].(map[string]interface{})
+
+	config.MaxAttempts = retry["max_attempts"].(int)
+
+	minBackoff, err := time.ParseDuration(retry["min_backoff"].(string))
+	if err != nil {
+		return config, fmt.Errorf("invalid retry min_backoff: %s", err)
+	}
+	config.MinBackoff = minBackoff
+
+	maxBackoff, err := time.ParseDuration(retry["max_backoff"].(string))
+	if err != nil {
+		return config, fmt.Errorf("invalid retry max_backoff: %s", err)
+	}
+	config.MaxBackoff = maxBackoff
+
+	if config.MaxBackoff < config.MinBackoff {
+		return config, fmt.Errorf("retry max_backoff (%s) must not be less than min_backoff (%s)", config.MaxBackoff, config.MinBackoff)
+	}
+
+	return config, nil
+}
+
+func validateDuration(v interface{}, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: invalid duration %q: %s", k, value, err)}
+	}
+
+	if duration < 0 {
+		return nil, []error{fmt.Errorf("%s: duration must not be negative, got %s", k, value)}
+	}
+
+	return nil, nil
+}
+
+// retryTransport implements http.RoundTripper and retries transient API errors.
+// Rate limited responses are retried for every method since the API didn't process them,
+// other transient errors only for idempotent methods so a server order is never sent twice.
+type retryTransport struct {
+	transport http.RoundTripper
+	config    RetryConfig
+}
+
+// newRetryTransport wraps transport with retries, http.DefaultTransport is used when transport is nil
+func newRetryTransport(transport http.RoundTripper, config RetryConfig) *retryTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &retryTransport{
+		transport: transport,
+		config:    config,
+	}
+}
+
+// RoundTrip executes the request and retries it on transient errors
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := bufferRequestBody(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := t.transport.RoundTrip(req)
+
+		if attempt >= t.config.MaxAttempts || !shouldRetryRequest(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+
+		if err != nil {
+			log.Printf("[WARN] %s %s failed: %s, retrying in %s (attempt %d of %d)", req.Method, req.URL.Path, err, wait, attempt, t.config.MaxAttempts)
+		} else {
+			log.Printf("[WARN] %s %s returned %s, retrying in %s (attempt %d of %d)", req.Method, req.URL.Path, resp.Status, wait, attempt, t.config.MaxAttempts)
+
+			// drain the body so the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns how long to wait before the next attempt.
+// Retry-After takes precedence over the exponential backoff.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return wait
+		}
+	}
+
+	wait := t.config.MinBackoff
+	for i := 1; i < attempt && wait < t.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+
+	if wait > t.config.MaxBackoff {
+		wait = t.config.MaxBackoff
+	}
+
+	// add up to 10% of jitter to spread out parallel resources
+	if jitter := int64(wait / 10); jitter > 0 {
+		wait += time.Duration(rand.Int63n(jitter))
+	}
+
+	return wait
+}
+
+// shouldRetryRequest reports whether the response or error is transient
+func shouldRetryRequest(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotentMethod(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotentMethod(req.Method)
+	}
+
+	return false
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// parseRetryAfter parses Retry-After header value in seconds or HTTP-date format
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// bufferRequestBody makes the request body replayable for retries
+func bufferRequestBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package serverscom
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryClient(maxAttempts int) *http.Client {
+	return &http.Client{
+		Transport: newRetryTransport(nil, RetryConfig{
+			MaxAttempts: maxAttempts,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  10 * time.Millisecond,
+		}),
+	}
+}
+
+func TestRetryTransport_RateLimited(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"hostname":"node"}` {
+			t.Errorf("unexpected body on attempt %d: %q", atomic.LoadInt32(&calls)+1, body)
+		}
+
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	resp, err := testRetryClient(5).Post(server.URL, "application/json", strings.NewReader(`{"hostname":"node"}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_NonIdempotentNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	resp, err := testRetryClient(5).Post(server.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if calls != 1 {
+		t.Fatalf("expected POST to be sent once, got %d calls", calls)
+	}
+}
+
+func TestRetryTransport_MaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	resp, err := testRetryClient(3).Get(server.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("7"); !ok || wait != 7*time.Second {
+		t.Fatalf("expected 7s, got %s (%v)", wait, ok)
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if wait, ok := parseRetryAfter(date); !ok || wait <= 59*time.Minute {
+		t.Fatalf("expected about 1h, got %s (%v)", wait, ok)
+	}
+
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Fatal("expected invalid value to be ignored")
+	}
+}
@@ -0,0 +1,160 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+// powerStatuses maps power_state values to the power statuses reported by the api
+var powerStatuses = map[string]string{
+	"on":  "powered_on",
+	"off": "powered_off",
+}
+
+func powerStateSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.StringInSlice([]string{"on", "off"}, false),
+		Description:  "Power state of the server, on or off",
+	}
+}
+
+// powerStateFromStatus returns power_state for the power status reported by the api,
+// an empty string is returned for transitional and unknown statuses
+func powerStateFromStatus(powerStatus string) string {
+	for state, status := range powerStatuses {
+		if status == powerStatus {
+			return state
+		}
+	}
+
+	return ""
+}
+
+// setPowerState sets power_state from the power status, transitional statuses keep the previous value
+func setPowerState(d *schema.ResourceData, powerStatus string) {
+	if state := powerStateFromStatus(powerStatus); state != "" {
+		d.Set("power_state", state)
+	}
+}
+
+// serverPower switches the power of a single server type
+type serverPower struct {
+	name         string
+	powerOn      func(ctx context.Context, id string) error
+	powerOff     func(ctx context.Context, id string) error
+	status       func(ctx context.Context, id string) (string, error)
+	pollInterval time.Duration
+}
+
+func dedicatedServerPower(client *scgo.Client) serverPower {
+	return serverPower{
+		name: "dedicated server",
+		powerOn: func(ctx context.Context, id string) error {
+			_, err := client.Hosts.PowerOnDedicatedServer(ctx, id)
+			return err
+		},
+		powerOff: func(ctx context.Context, id string) error {
+			_, err := client.Hosts.PowerOffDedicatedServer(ctx, id)
+			return err
+		},
+		status: func(ctx context.Context, id string) (string, error) {
+			server, err := client.Hosts.GetDedicatedServer(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return server.PowerStatus, nil
+		},
+		pollInterval: waiterDelay(10 * time.Second),
+	}
+}
+
+func sbmServerPower(client *scgo.Client) serverPower {
+	return serverPower{
+		name: "SBM server",
+		powerOn: func(ctx context.Context, id string) error {
+			_, err := client.Hosts.PowerOnSBMServer(ctx, id)
+			return err
+		},
+		powerOff: func(ctx context.Context, id string) error {
+			_, err := client.Hosts.PowerOffSBMServer(ctx, id)
+			return err
+		},
+		status: func(ctx context.Context, id string) (string, error) {
+			server, err := client.Hosts.GetSBMServer(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return server.PowerStatus, nil
+		},
+		pollInterval: waiterDelay(10 * time.Second),
+	}
+}
+
+// applyPowerState switches the server power to the state and waits for the api to report it.
+// The state is passed in, since reading the server during create overwrites the configured power_state.
+func applyPowerState(ctx context.Context, d *schema.ResourceData, power serverPower, state string, timeoutKey string) error {
+	target, ok := powerStatuses[state]
+	if !ok {
+		return nil
+	}
+
+	current, err := power.status(ctx, d.Id())
+	if err != nil {
+		return fmt.Errorf("Error retrieving %s (%s) power status: %s", power.name, d.Id(), err)
+	}
+
+	if current == target {
+		d.Set("power_state", state)
+		return nil
+	}
+
+	switchPower := power.powerOn
+	if state == "off" {
+		switchPower = power.powerOff
+	}
+
+	if err := switchPower(ctx, d.Id()); err != nil {
+		return fmt.Errorf("Error powering %s %s (%s): %s", state, power.name, d.Id(), err)
+	}
+
+	log.Printf("[INFO] Waiting for %s (%s) to have power status of %s", power.name, d.Id(), target)
+
+	var pending []string
+	for _, status := range []string{"powered_on", "powered_off", "powering_on", "powering_off", "unknown"} {
+		if status != target {
+			pending = append(pending, status)
+		}
+	}
+
+	stateConf := &retry.StateChangeConf{
+		Pending: pending,
+		Target:  []string{target},
+		Refresh: func() (interface{}, string, error) {
+			status, err := power.status(ctx, d.Id())
+			if err != nil {
+				return nil, "", err
+			}
+			return status, status, nil
+		},
+		Timeout:      d.Timeout(timeoutKey),
+		PollInterval: power.pollInterval,
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("Error waiting for %s (%s) to be powered %s: %s", power.name, d.Id(), state, err)
+	}
+
+	d.Set("power_state", state)
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package serverscom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestPowerStateFromStatus(t *testing.T) {
+	cases := map[string]string{
+		"powered_on":   "on",
+		"powered_off":  "off",
+		"powering_off": "",
+		"unknown":      "",
+	}
+
+	for status, expected := range cases {
+		if state := powerStateFromStatus(status); state != expected {
+			t.Errorf("expected %q for %q, got %q", expected, status, state)
+		}
+	}
+}
+
+func TestApplyPowerState(t *testing.T) {
+	ctx := context.Background()
+	client := testFakeAPIClient(t)
+	meta := &ProviderMeta{Client: client}
+
+	id := testCreateSBMServer(t, client, "node-1", nil)
+
+	d := schema.TestResourceDataRaw(t, resourceServerscomSBM().Schema, map[string]interface{}{})
+	d.SetId(id)
+
+	power := sbmServerPower(client)
+	power.pollInterval = time.Millisecond
+
+	calls := make(map[string]int)
+	powerOn, powerOff := power.powerOn, power.powerOff
+	power.powerOn = func(ctx context.Context, id string) error {
+		calls["on"]++
+		return powerOn(ctx, id)
+	}
+	power.powerOff = func(ctx context.Context, id string) error {
+		calls["off"]++
+		return powerOff(ctx, id)
+	}
+
+	for _, state := range []string{"off", "off", "on"} {
+		if err := applyPowerState(ctx, d, power, state, schema.TimeoutUpdate); err != nil {
+			t.Fatalf("unexpected error powering %s: %s", state, err)
+		}
+
+		if got := d.Get("power_state"); got != state {
+			t.Fatalf("expected power_state %s, got %v", state, got)
+		}
+
+		sbm, err := client.Hosts.GetSBMServer(ctx, id)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if sbm.PowerStatus != powerStatuses[state] {
+			t.Fatalf("expected power status %s, got %s", powerStatuses[state], sbm.PowerStatus)
+		}
+	}
+
+	// the server is already off when it's powered off for the second time
+	if calls["off"] != 1 || calls["on"] != 1 {
+		t.Fatalf("expected one power off and one power on call, got %v", calls)
+	}
+
+	// the server powered off outside of terraform is reported once the transition is finished
+	if _, err := client.Hosts.PowerOffSBMServer(ctx, id); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, expected := range []string{"on", "off"} {
+		if diags := resourceServerscomSBMRead(ctx, d, meta); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+
+		if got := d.Get("power_state"); got != expected {
+			t.Fatalf("expected power_state %s, got %v", expected, got)
+		}
+	}
+}
@@ -5,28 +5,89 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	scgo "github.com/serverscom/serverscom-go-client/pkg"
 )
 
 var (
-	serverCollector *ServerCollector
-
-	// when timer expires the collector triggers the ExecuteRequests method
-	serverCollectorTimer = 5 * time.Second
+	defaultServerBatchWindow  = 5 * time.Second
+	defaultServerBatchMaxWait = 30 * time.Second
+	defaultServerBatchMaxSize = 0
 )
 
+// ServerCollectorConfig describes how server create requests are grouped into batches
+type ServerCollectorConfig struct {
+	// Window is the time without new requests after which the batch is sent, 0 disables batching
+	Window time.Duration
+
+	// MaxWait limits how long the first request of the batch waits, 0 means no limit
+	MaxWait time.Duration
+
+	// MaxSize is the maximum number of servers in a single order, 0 means no limit
+	MaxSize int
+}
+
+// DefaultServerCollectorConfig returns the batching config used when the provider has no batching settings
+func DefaultServerCollectorConfig() ServerCollectorConfig {
+	return ServerCollectorConfig{
+		Window:  defaultServerBatchWindow,
+		MaxWait: defaultServerBatchMaxWait,
+		MaxSize: defaultServerBatchMaxSize,
+	}
+}
+
+// expandServerCollectorConfig builds ServerCollectorConfig from the provider server_batch_* settings
+func expandServerCollectorConfig(d *schema.ResourceData) (ServerCollectorConfig, error) {
+	config := DefaultServerCollectorConfig()
+
+	window, err := time.ParseDuration(d.Get("server_batch_window").(string))
+	if err != nil {
+		return config, fmt.Errorf("invalid server_batch_window: %s", err)
+	}
+	config.Window = window
+
+	maxWait, err := time.ParseDuration(d.Get("server_batch_max_wait").(string))
+	if err != nil {
+		return config, fmt.Errorf("invalid server_batch_max_wait: %s", err)
+	}
+	config.MaxWait = maxWait
+
+	if config.MaxWait != 0 && config.MaxWait < config.Window {
+		return config, fmt.Errorf("server_batch_max_wait (%s) must not be less than server_batch_window (%s)", config.MaxWait, config.Window)
+	}
+
+	config.MaxSize = d.Get("server_batch_max_size").(int)
+
+	return config, nil
+}
+
 // ServerCollector represents server collector abstraction.
 // It accept requests from create events for 'serverscom_dedicated_server' resources
-// groups it by checksum based on all server fields except 'hosts' and create these servers in one batch request
+// groups it by checksum based on all server fields except 'hosts' and create these servers in one batch request.
+// The batch is sent when no requests arrive during the window, when the first request waited for MaxWait
+// or when the group reaches MaxSize servers, whichever happens first.
 type ServerCollector struct {
 	Client   *scgo.Client
+	Config   ServerCollectorConfig
 	Requests map[string]map[string][]*Request
 	Mutex    sync.Mutex
 	Timer    *time.Timer
+
+	// pendingSince is the time the oldest queued request was added
+	pendingSince time.Time
+
+	// createBatch sends one batch of requests, it's replaced in tests
+	createBatch func(ctx context.Context, requests []*Request)
 }
 
 // ServerCreateInput represents server (sbm, dedicated, ...) create input interface
@@ -43,6 +104,7 @@ type ServersResponse interface {
 
 // Request represents a request with create server input and result channel
 type Request struct {
+	Ctx        context.Context
 	Input      ServerCreateInput
 	ResultChan chan Result
 }
@@ -55,16 +117,24 @@ type Result struct {
 }
 
 // NewServerCollector creates new ServerCollector
-func NewServerCollector(client *scgo.Client) *ServerCollector {
+func NewServerCollector(client *scgo.Client, config ServerCollectorConfig) *ServerCollector {
+	// the timer is started by the first request
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+
 	return &ServerCollector{
 		Client:   client,
+		Config:   config,
 		Requests: make(map[string]map[string][]*Request),
-		Timer:    time.NewTimer(serverCollectorTimer),
+		Timer:    timer,
+		createBatch: func(ctx context.Context, requests []*Request) {
+			CreateServersBatch(ctx, client, requests)
+		},
 	}
 }
 
 // AddRequest adds request to server collector
-// Each request resets the serverCollectorTimer
+// Each request postpones the batch by the window, but not further than MaxWait after the first queued request
 func (sc *ServerCollector) AddRequest(ctx context.Context, resourceType string, input ServerCreateInput) (<-chan Result, error) {
 	sc.Mutex.Lock()
 	defer sc.Mutex.Unlock()
@@ -74,40 +144,139 @@ func (sc *ServerCollector) AddRequest(ctx context.Context, resourceType string,
 	if err != nil {
 		return nil, err
 	}
+
+	req := &Request{
+		Ctx:        ctx,
+		Input:      input,
+		ResultChan: resultChan,
+	}
+
+	// batching is disabled, every request is sent on its own
+	if sc.Config.Window <= 0 {
+		go sc.executeBatch([]*Request{req})
+		return resultChan, nil
+	}
+
 	if sc.Requests[resourceType] == nil {
 		sc.Requests[resourceType] = make(map[string][]*Request)
 	}
-	sc.Requests[resourceType][checksum] = append(
-		sc.Requests[resourceType][checksum],
-		&Request{
-			Input:      input,
-			ResultChan: resultChan,
-		},
-	)
+	sc.Requests[resourceType][checksum] = append(sc.Requests[resourceType][checksum], req)
+
+	if sc.Config.MaxSize > 0 && countHosts(sc.Requests[resourceType][checksum]) >= sc.Config.MaxSize {
+		requests := sc.Requests[resourceType][checksum]
+		delete(sc.Requests[resourceType], checksum)
+
+		log.Printf("[DEBUG] Servers batch of %d %s requests reached server_batch_max_size, sending it", len(requests), resourceType)
+		go sc.executeBatch(requests)
+	}
+
+	sc.resetTimer()
+
+	return resultChan, nil
+}
 
+// resetTimer schedules the batch for the end of the window or MaxWait after the oldest queued request
+func (sc *ServerCollector) resetTimer() {
 	if !sc.Timer.Stop() {
 		select {
 		case <-sc.Timer.C:
 		default:
 		}
 	}
-	sc.Timer.Reset(serverCollectorTimer)
 
-	return resultChan, nil
+	if !sc.hasRequests() {
+		sc.pendingSince = time.Time{}
+		return
+	}
+
+	now := time.Now()
+	if sc.pendingSince.IsZero() {
+		sc.pendingSince = now
+	}
+
+	wait := sc.Config.Window
+	if sc.Config.MaxWait > 0 {
+		wait = min(wait, sc.pendingSince.Add(sc.Config.MaxWait).Sub(now))
+	}
+
+	sc.Timer.Reset(max(wait, 0))
+}
+
+func (sc *ServerCollector) hasRequests() bool {
+	for _, checksums := range sc.Requests {
+		if len(checksums) > 0 {
+			return true
+		}
+	}
+
+	return false
 }
 
-// ExecuteRequests triggers when timer expires and runs CreateServersBatch for each requests checksum group
+// ExecuteRequests triggers when timer expires and runs CreateServersBatch for each requests checksum group.
+// Requests cancelled while waiting in the queue are dropped and receive the context error.
 func (sc *ServerCollector) ExecuteRequests() {
 	sc.Mutex.Lock()
 	defer sc.Mutex.Unlock()
 
-	ctx := context.Background()
-	for resourceType, checksums := range sc.Requests {
+	for _, checksums := range sc.Requests {
 		for checksum, requests := range checksums {
-			CreateServersBatch(ctx, sc.Client, requests)
-			sc.Requests[resourceType][checksum] = nil
+			sc.executeBatch(requests)
+			delete(checksums, checksum)
+		}
+	}
+	sc.pendingSince = time.Time{}
+}
+
+// executeBatch sends requests of the same checksum group in one order
+func (sc *ServerCollector) executeBatch(requests []*Request) {
+	requests = activeRequests(requests)
+	if len(requests) == 0 {
+		return
+	}
+
+	// the batch is shared by several resources, so once sent it must not be aborted
+	// by cancellation of any of them, otherwise ordered servers would be lost from the state
+	sc.createBatch(context.Background(), requests)
+}
+
+func countHosts(requests []*Request) int {
+	var count int
+	for _, req := range requests {
+		count += len(req.Input.GetHosts())
+	}
+
+	return count
+}
+
+// requestHostnames returns hostnames of the requests, hosts of a single request are joined with "+"
+func requestHostnames(requests []*Request) []string {
+	hostnames := make([]string, 0, len(requests))
+	for _, req := range requests {
+		var requestHostnames []string
+		for _, host := range req.Input.GetHosts() {
+			hostname, _ := getHostHostname(host)
+			requestHostnames = append(requestHostnames, hostname)
+		}
+		hostnames = append(hostnames, strings.Join(requestHostnames, "+"))
+	}
+
+	return hostnames
+}
+
+// activeRequests returns requests which context is not cancelled yet,
+// cancelled requests are completed with the context error
+func activeRequests(requests []*Request) []*Request {
+	active := make([]*Request, 0, len(requests))
+	for _, req := range requests {
+		if req.Ctx != nil && req.Ctx.Err() != nil {
+			req.ResultChan <- Result{Error: req.Ctx.Err()}
+			close(req.ResultChan)
+			continue
 		}
+		active = append(active, req)
 	}
+
+	return active
 }
 
 // Run runs the collector to listen for requests
@@ -120,59 +289,212 @@ func (sc *ServerCollector) Run() {
 	}()
 }
 
-// CreateServersBatch aggregates hostnames from all requests in one input and creates these servers in one api request
+// maxBatchAttempts limits how many times the batch is resent without hosts rejected by the api
+const maxBatchAttempts = 3
+
+// hostErrorKey matches validation error keys of a single host, e.g. hosts.1.hostname or hosts[1].hostname
+var hostErrorKey = regexp.MustCompile(`^hosts(?:\.(\d+)|\[(\d+)\])\.?(.*)$`)
+
+// CreateServersBatch aggregates hostnames from all requests in one input and creates these servers in one api request.
+// Every request receives its own result: hosts rejected locally or by the api validation
+// are removed from the batch with their own error and the rest of the servers are still ordered.
 func CreateServersBatch(ctx context.Context, client *scgo.Client, requests []*Request) {
-	if len(requests) == 0 {
-		return
-	}
-	for _, req := range requests {
-		defer close(req.ResultChan)
+	createServersBatch(ctx, requests, func(ctx context.Context, input ServerCreateInput) (ServersResponse, error) {
+		switch v := input.(type) {
+		case *DedicatedServerCreateInput:
+			resp, err := client.Hosts.CreateDedicatedServers(ctx, v.DedicatedServerCreateInput)
+			return &DedicatedServerResponse{servers: resp}, err
+		case *SBMServerCreateInput:
+			resp, err := client.Hosts.CreateSBMServers(ctx, v.SBMServerCreateInput)
+			return &SBMServerResponse{servers: resp}, err
+		default:
+			return nil, fmt.Errorf("Unknown resource type: %T", v)
+		}
+	})
+}
+
+func createServersBatch(ctx context.Context, requests []*Request, create func(context.Context, ServerCreateInput) (ServersResponse, error)) {
+	complete := func(req *Request, result Result) {
+		req.ResultChan <- result
+		close(req.ResultChan)
 	}
 
-	// combine hosts input
-	// for any duplicate hostname return error
+	// reject requests with unknown host types or hostnames duplicated in the batch
 	uniqueHostnames := make(map[string]struct{})
-	var combinedHosts []interface{}
-	result := Result{}
+	var accepted []*Request
 	for _, req := range requests {
-		for _, host := range req.Input.GetHosts() {
-			hostname, err := getHostHostname(host)
-			if err != nil {
-				result.Error = err
-				req.ResultChan <- result
-				continue
+		if err := checkRequestHosts(req, uniqueHostnames); err != nil {
+			complete(req, Result{Error: err})
+			continue
+		}
+		accepted = append(accepted, req)
+	}
+
+	for attempt := 1; len(accepted) > 0; attempt++ {
+		// owners maps the index of the host in the batch to its request
+		var combinedHosts []interface{}
+		var owners []*Request
+		for _, req := range accepted {
+			for _, host := range req.Input.GetHosts() {
+				combinedHosts = append(combinedHosts, host)
+				owners = append(owners, req)
 			}
-			if _, ok := uniqueHostnames[hostname]; ok {
-				result.Error = fmt.Errorf("duplicate hostname found: %s", hostname)
-				req.ResultChan <- result
+		}
+
+		input := accepted[0].Input
+		originalHosts := input.GetHosts()
+		input.SetHosts(combinedHosts)
+		log.Printf("[DEBUG] Ordering %d servers in one request (attempt %d): %s", len(combinedHosts), attempt, strings.Join(requestHostnames(accepted), ", "))
+		servers, err := create(ctx, input)
+		input.SetHosts(originalHosts)
+
+		if err == nil {
+			for _, req := range accepted {
+				complete(req, Result{Servers: servers})
+			}
+			return
+		}
+
+		hostErrs, sharedErr := splitHostErrors(err)
+		if len(hostErrs) == 0 || sharedErr != nil || attempt == maxBatchAttempts {
+			for _, req := range accepted {
+				complete(req, Result{Error: describeAPIError(err)})
+			}
+			return
+		}
+
+		// requests which hosts caused the error receive it, the rest are sent again
+		rejected := make(map[*Request]error)
+		for index, hostErr := range hostErrs {
+			if index >= 0 && index < len(owners) {
+				rejected[owners[index]] = errors.Join(rejected[owners[index]], hostErr)
+			}
+		}
+
+		// resending the same batch gets the same error
+		if len(rejected) == 0 {
+			for _, req := range accepted {
+				complete(req, Result{Error: describeAPIError(err)})
+			}
+			return
+		}
+
+		var remaining []*Request
+		for _, req := range accepted {
+			if hostErr, ok := rejected[req]; ok {
+				complete(req, Result{Error: hostErr})
 				continue
 			}
-			uniqueHostnames[hostname] = struct{}{}
-			combinedHosts = append(combinedHosts, host)
+			remaining = append(remaining, req)
 		}
+
+		log.Printf("[WARN] Servers batch rejected %d of %d requests, retrying with the rest", len(rejected), len(accepted))
+		accepted = remaining
 	}
+}
 
-	firstReq := requests[0]
-	firstReq.Input.SetHosts(combinedHosts)
+// checkRequestHosts checks hosts of the request have known types and hostnames not used by other requests of the batch
+func checkRequestHosts(req *Request, uniqueHostnames map[string]struct{}) error {
+	var hostnames []string
+	for _, host := range req.Input.GetHosts() {
+		hostname, err := getHostHostname(host)
+		if err != nil {
+			return err
+		}
+		if _, ok := uniqueHostnames[hostname]; ok {
+			return fmt.Errorf("duplicate hostname found: %s", hostname)
+		}
+		hostnames = append(hostnames, hostname)
+	}
 
-	switch v := firstReq.Input.(type) {
-	case *DedicatedServerCreateInput:
-		resp, err := client.Hosts.CreateDedicatedServers(ctx, v.DedicatedServerCreateInput)
-		servers := &DedicatedServerResponse{servers: resp}
-		result.Servers = servers
-		result.Error = err
-	case *SBMServerCreateInput:
-		resp, err := client.Hosts.CreateSBMServers(ctx, v.SBMServerCreateInput)
-		servers := &SBMServerResponse{servers: resp}
-		result.Servers = servers
-		result.Error = err
-	default:
-		result.Error = fmt.Errorf("Unknown resource type: %T\n", v)
+	for _, hostname := range hostnames {
+		uniqueHostnames[hostname] = struct{}{}
 	}
 
-	for _, req := range requests {
-		req.ResultChan <- result
+	return nil
+}
+
+// splitHostErrors splits api validation errors by the hosts which caused them.
+// sharedErr describes errors of the fields common for all hosts.
+func splitHostErrors(err error) (map[int]error, error) {
+	message, fieldErrors := apiErrorDetails(err)
+	if fieldErrors == nil {
+		return nil, err
+	}
+
+	hostErrs := make(map[int]error)
+	var sharedErrs []string
+
+	for _, field := range sortedKeys(fieldErrors) {
+		match := hostErrorKey.FindStringSubmatch(field)
+		if match == nil {
+			sharedErrs = append(sharedErrs, formatFieldError(field, fieldErrors[field]))
+			continue
+		}
+
+		indexValue := match[1]
+		if indexValue == "" {
+			indexValue = match[2]
+		}
+		index, _ := strconv.Atoi(indexValue)
+
+		hostErr := fmt.Errorf("%s: %s", message, formatFieldError(match[3], fieldErrors[field]))
+		hostErrs[index] = errors.Join(hostErrs[index], hostErr)
+	}
+
+	if len(sharedErrs) > 0 {
+		return hostErrs, fmt.Errorf("%s: %s", message, strings.Join(sharedErrs, "; "))
+	}
+
+	return hostErrs, nil
+}
+
+// describeAPIError adds field validation errors to the api error message
+func describeAPIError(err error) error {
+	message, fieldErrors := apiErrorDetails(err)
+	if len(fieldErrors) == 0 {
+		return err
+	}
+
+	var details []string
+	for _, field := range sortedKeys(fieldErrors) {
+		details = append(details, formatFieldError(field, fieldErrors[field]))
+	}
+
+	return fmt.Errorf("%s: %s", message, strings.Join(details, "; "))
+}
+
+// apiErrorDetails returns the message and field errors of api validation errors
+func apiErrorDetails(err error) (string, map[string][]string) {
+	var unprocessableEntityErr *scgo.UnprocessableEntityError
+	if errors.As(err, &unprocessableEntityErr) {
+		return unprocessableEntityErr.Message, unprocessableEntityErr.Errors
+	}
+
+	var badRequestErr *scgo.BadRequestError
+	if errors.As(err, &badRequestErr) {
+		return badRequestErr.Message, badRequestErr.Errors
 	}
+
+	return err.Error(), nil
+}
+
+func formatFieldError(field string, messages []string) string {
+	if field == "" {
+		return strings.Join(messages, ", ")
+	}
+
+	return fmt.Sprintf("%s %s", field, strings.Join(messages, ", "))
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
 }
 
 // calculateServerChecksum generate checksum for server create input excepting the Hosts field
@@ -199,6 +521,6 @@ func getHostHostname(hostInput interface{}) (string, error) {
 	case scgo.SBMServerHostInput:
 		return v.Hostname, nil
 	default:
-		return "", fmt.Errorf("Unknown host input type: %T\n", v)
+		return "", fmt.Errorf("Unknown host input type: %T", v)
 	}
 }
@@ -0,0 +1,234 @@
+package serverscom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	scgo "github.com/serverscom/serverscom-go-client/pkg"
+)
+
+func TestActiveRequests_DropsCancelled(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cancelled := &Request{Ctx: cancelledCtx, ResultChan: make(chan Result, 1)}
+	active := &Request{Ctx: context.Background(), ResultChan: make(chan Result, 1)}
+
+	requests := activeRequests([]*Request{cancelled, active})
+
+	if len(requests) != 1 || requests[0] != active {
+		t.Fatalf("expected only the active request, got %v", requests)
+	}
+
+	result, ok := <-cancelled.ResultChan
+	if !ok || result.Error != context.Canceled {
+		t.Fatalf("expected context.Canceled for the cancelled request, got %v", result.Error)
+	}
+
+	if _, ok := <-cancelled.ResultChan; ok {
+		t.Fatal("expected result channel of the cancelled request to be closed")
+	}
+}
+
+func testSBMRequest(hostnames ...string) *Request {
+	input := &SBMServerCreateInput{}
+	for _, hostname := range hostnames {
+		input.Hosts = append(input.Hosts, scgo.SBMServerHostInput{Hostname: hostname})
+	}
+
+	return &Request{Ctx: context.Background(), Input: input, ResultChan: make(chan Result, 1)}
+}
+
+// testCreateSBMServers creates servers for all hosts except the invalid ones
+func testCreateSBMServers(calls *int, invalid ...string) func(context.Context, ServerCreateInput) (ServersResponse, error) {
+	return func(ctx context.Context, input ServerCreateInput) (ServersResponse, error) {
+		*calls++
+
+		errs := make(map[string][]string)
+		var servers []scgo.SBMServer
+		for i, host := range input.(*SBMServerCreateInput).Hosts {
+			for _, hostname := range invalid {
+				if host.Hostname == hostname {
+					errs[fmt.Sprintf("hosts.%d.hostname", i)] = []string{"is invalid"}
+				}
+			}
+			servers = append(servers, scgo.SBMServer{ID: host.Hostname + "-id", Title: host.Hostname})
+		}
+
+		if len(errs) > 0 {
+			return nil, &scgo.UnprocessableEntityError{Message: "Validation failed", Errors: errs}
+		}
+
+		return &SBMServerResponse{servers: servers}, nil
+	}
+}
+
+func TestCreateServersBatch_RejectsOnlyInvalidHosts(t *testing.T) {
+	valid := testSBMRequest("node-1", "node-2")
+	invalid := testSBMRequest("node_3")
+	duplicate := testSBMRequest("node-1")
+
+	var calls int
+	createServersBatch(context.Background(), []*Request{valid, invalid, duplicate}, testCreateSBMServers(&calls, "node_3"))
+
+	if calls != 2 {
+		t.Fatalf("expected 2 api calls, got %d", calls)
+	}
+
+	result := <-valid.ResultChan
+	if result.Error != nil || result.Servers.GetIdByHostname("node-2") != "node-2-id" {
+		t.Fatalf("expected servers of the valid request, got %v", result.Error)
+	}
+	if result.Servers.GetIdByHostname("node_3") != "" {
+		t.Fatal("expected invalid host to be excluded from the batch")
+	}
+
+	result = <-invalid.ResultChan
+	if result.Error == nil || result.Error.Error() != "Validation failed: hostname is invalid" {
+		t.Fatalf("expected hostname error for the invalid request, got %v", result.Error)
+	}
+
+	result = <-duplicate.ResultChan
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "duplicate hostname found: node-1") {
+		t.Fatalf("expected duplicate hostname error, got %v", result.Error)
+	}
+
+	for _, req := range []*Request{valid, invalid, duplicate} {
+		if _, ok := <-req.ResultChan; ok {
+			t.Fatal("expected result channel to be closed")
+		}
+	}
+}
+
+func TestCreateServersBatch_SharedError(t *testing.T) {
+	requests := []*Request{testSBMRequest("node-1"), testSBMRequest("node-2")}
+
+	var calls int
+	createServersBatch(context.Background(), requests, func(ctx context.Context, input ServerCreateInput) (ServersResponse, error) {
+		calls++
+		return nil, &scgo.UnprocessableEntityError{
+			Message: "Validation failed",
+			Errors: map[string][]string{
+				"location_id":      {"is invalid"},
+				"hosts.0.hostname": {"is taken"},
+			},
+		}
+	})
+
+	if calls != 1 {
+		t.Fatalf("expected 1 api call, got %d", calls)
+	}
+
+	for _, req := range requests {
+		result := <-req.ResultChan
+		if result.Error == nil || result.Error.Error() != "Validation failed: hosts.0.hostname is taken; location_id is invalid" {
+			t.Fatalf("expected shared error, got %v", result.Error)
+		}
+	}
+}
+
+func TestCreateServersBatch_UnknownHostError(t *testing.T) {
+	requests := []*Request{testSBMRequest("node-1"), testSBMRequest("node-2")}
+
+	var calls int
+	createServersBatch(context.Background(), requests, func(ctx context.Context, input ServerCreateInput) (ServersResponse, error) {
+		calls++
+		return nil, &scgo.UnprocessableEntityError{
+			Message: "Validation failed",
+			Errors:  map[string][]string{"hosts.5.hostname": {"is taken"}},
+		}
+	})
+
+	if calls != 1 {
+		t.Fatalf("expected 1 api call, got %d", calls)
+	}
+
+	for _, req := range requests {
+		result := <-req.ResultChan
+		if result.Error == nil || result.Error.Error() != "Validation failed: hosts.5.hostname is taken" {
+			t.Fatalf("expected the api error, got %v", result.Error)
+		}
+	}
+}
+
+// testServerCollector returns a collector which reports every sent batch to the channel
+func testServerCollector(config ServerCollectorConfig) (*ServerCollector, chan []*Request) {
+	batches := make(chan []*Request, 10)
+
+	sc := NewServerCollector(nil, config)
+	sc.createBatch = func(ctx context.Context, requests []*Request) {
+		batches <- requests
+	}
+	sc.Run()
+
+	return sc, batches
+}
+
+func testAddRequests(t *testing.T, sc *ServerCollector, hostnames ...string) {
+	for _, hostname := range hostnames {
+		if _, err := sc.AddRequest(context.Background(), "sbm", testSBMRequest(hostname).Input); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+func TestServerCollector_MaxSize(t *testing.T) {
+	sc, batches := testServerCollector(ServerCollectorConfig{Window: time.Hour, MaxSize: 2})
+
+	testAddRequests(t, sc, "node-1", "node-2", "node-3")
+
+	select {
+	case batch := <-batches:
+		if len(batch) != 2 {
+			t.Fatalf("expected batch of 2 requests, got %d", len(batch))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected full batch to be sent without waiting for the window")
+	}
+
+	select {
+	case batch := <-batches:
+		t.Fatalf("expected the last request to wait for the window, got batch of %d", len(batch))
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestServerCollector_MaxWait(t *testing.T) {
+	sc, batches := testServerCollector(ServerCollectorConfig{Window: 100 * time.Millisecond, MaxWait: 250 * time.Millisecond})
+
+	// a steady trickle of requests keeps resetting the window, adding all of them takes at least 450ms
+	const requests = 10
+	for i := 0; i < requests; i++ {
+		testAddRequests(t, sc, fmt.Sprintf("node-%d", i))
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	select {
+	case batch := <-batches:
+		if len(batch) >= requests {
+			t.Fatalf("expected the batch to be sent after max wait before all requests were added, got %d requests", len(batch))
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected batch to be sent")
+	}
+}
+
+func TestServerCollector_BatchingDisabled(t *testing.T) {
+	sc, batches := testServerCollector(ServerCollectorConfig{})
+
+	testAddRequests(t, sc, "node-1", "node-2")
+
+	for i := 0; i < 2; i++ {
+		select {
+		case batch := <-batches:
+			if len(batch) != 1 {
+				t.Fatalf("expected every request to be sent on its own, got batch of %d", len(batch))
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected request to be sent immediately")
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package serverscom
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+const (
+	// maxSuggestions limits the number of "did you mean" values
+	maxSuggestions = 3
+
+	// maxValidValues limits the number of valid values listed in the error
+	maxValidValues = 20
+)
+
+// optionNotFoundError is returned when an order option can't be resolved by its name or code
+type optionNotFoundError struct {
+	Kind  string
+	Value string
+	Valid []string
+
+	// Path points at the attribute holding the value, it's set when known by the lookup
+	Path cty.Path
+}
+
+func newOptionNotFoundError(kind, value string, valid []string) *optionNotFoundError {
+	return &optionNotFoundError{
+		Kind:  kind,
+		Value: value,
+		Valid: valid,
+	}
+}
+
+func (e *optionNotFoundError) Error() string {
+	if detail := e.Detail(); detail != "" {
+		return fmt.Sprintf("%s. %s", e.Summary(), detail)
+	}
+
+	return e.Summary()
+}
+
+// Summary returns the short description of the error
+func (e *optionNotFoundError) Summary() string {
+	return fmt.Sprintf("Can't find %s by: %s", e.Kind, e.Value)
+}
+
+// Detail returns the closest values and the list of valid values
+func (e *optionNotFoundError) Detail() string {
+	if len(e.Valid) == 0 {
+		return fmt.Sprintf("There are no available values of %s.", e.Kind)
+	}
+
+	var detail []string
+
+	if suggestions := suggestValues(e.Value, e.Valid); len(suggestions) > 0 {
+		detail = append(detail, fmt.Sprintf("Did you mean %s?", joinQuoted(suggestions, " or ")))
+	}
+
+	valid := e.Valid
+	if len(valid) > maxValidValues {
+		valid = valid[:maxValidValues]
+		detail = append(detail, fmt.Sprintf("Valid values: %s and %d more.", joinQuoted(valid, ", "), len(e.Valid)-maxValidValues))
+	} else {
+		detail = append(detail, fmt.Sprintf("Valid values: %s.", joinQuoted(valid, ", ")))
+	}
+
+	return strings.Join(detail, " ")
+}
+
+// withAttributePath sets path of the attribute for optionNotFoundError, other errors are returned as is
+func withAttributePath(err error, path cty.Path) error {
+	var notFound *optionNotFoundError
+	if errors.As(err, &notFound) && notFound.Path == nil {
+		notFound.Path = path
+	}
+
+	return err
+}
+
+// optionDiagnostics converts the lookup error to diagnostics pointing at the attribute
+func optionDiagnostics(err error, path cty.Path) diag.Diagnostics {
+	var notFound *optionNotFoundError
+	if !errors.As(err, &notFound) {
+		return diag.FromErr(err)
+	}
+
+	if notFound.Path != nil {
+		path = notFound.Path
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       notFound.Summary(),
+			Detail:        notFound.Detail(),
+			AttributePath: path,
+		},
+	}
+}
+
+// optionDiffError prefixes the lookup error with the attribute path,
+// errors returned by CustomizeDiff can't point at the attribute on their own
+func optionDiffError(err error, path cty.Path) error {
+	var notFound *optionNotFoundError
+	if !errors.As(err, &notFound) {
+		return err
+	}
+
+	if notFound.Path != nil {
+		path = notFound.Path
+	}
+
+	return fmt.Errorf("%s: %w", formatAttributePath(path), err)
+}
+
+// formatAttributePath returns the path in the flatmap form, e.g. slot.1.drive_model
+func formatAttributePath(path cty.Path) string {
+	var steps []string
+
+	for _, step := range path {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			steps = append(steps, s.Name)
+		case cty.IndexStep:
+			switch s.Key.Type() {
+			case cty.Number:
+				index, _ := s.Key.AsBigFloat().Int64()
+				steps = append(steps, strconv.FormatInt(index, 10))
+			case cty.String:
+				steps = append(steps, s.Key.AsString())
+			}
+		}
+	}
+
+	return strings.Join(steps, ".")
+}
+
+// suggestValues returns valid values closest to the value by edit distance.
+// Values containing the given one are suggested as well, e.g. "R440" for a server model.
+func suggestValues(value string, valid []string) []string {
+	type candidate struct {
+		value    string
+		distance int
+	}
+
+	normalized := normalizeString(value)
+	if normalized == "" {
+		return nil
+	}
+
+	threshold := len([]rune(normalized)) / 3
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	var candidates []candidate
+	for _, v := range valid {
+		normalizedValid := normalizeString(v)
+
+		distance := levenshteinDistance(normalized, normalizedValid)
+		if strings.Contains(normalizedValid, normalized) {
+			distance = 0
+		}
+
+		if distance <= threshold {
+			candidates = append(candidates, candidate{value: v, distance: distance})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].value)
+	}
+
+	return suggestions
+}
+
+// levenshteinDistance returns the number of single character edits needed to turn a into b
+func levenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+func joinQuoted(values []string, sep string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+
+	return strings.Join(quoted, sep)
+}
@@ -0,0 +1,94 @@
+package serverscom
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"sjc1", "sjc1", 0},
+		{"sjc", "sjc1", 1},
+		{"smj1", "sjc1", 2},
+		{"kitten", "sitting", 3},
+		{"", "ams1", 4},
+	}
+
+	for _, c := range cases {
+		if actual := levenshteinDistance(c.a, c.b); actual != c.expected {
+			t.Errorf("distance between %q and %q: expected %d, got %d", c.a, c.b, c.expected, actual)
+		}
+	}
+}
+
+func TestSuggestValues(t *testing.T) {
+	valid := []string{
+		"Ubuntu 16.04-server x86_64",
+		"Ubuntu 18.04-server x86_64",
+		"CentOS 7 x86_64",
+	}
+
+	suggestions := suggestValues("ubuntu 18.04 server x86_64", valid)
+	if len(suggestions) == 0 || suggestions[0] != "Ubuntu 18.04-server x86_64" {
+		t.Fatalf("expected closest operating system first, got %v", suggestions)
+	}
+
+	if suggestions := suggestValues("Debian 12", valid); len(suggestions) != 0 {
+		t.Fatalf("expected no suggestions for unrelated value, got %v", suggestions)
+	}
+
+	models := []string{"Dell R440 / 2xIntel Xeon Silver-4114 / 32 GB RAM / 1x480 GB SSD", "HP DL360"}
+	if suggestions := suggestValues("R440", models); !reflect.DeepEqual(suggestions, models[:1]) {
+		t.Fatalf("expected model containing the value to be suggested, got %v", suggestions)
+	}
+}
+
+func TestOptionNotFoundError(t *testing.T) {
+	err := newOptionNotFoundError("location", "SJC", []string{"SJC1", "AMS1"})
+
+	expected := `Can't find location by: SJC. Did you mean "SJC1"? Valid values: "SJC1", "AMS1".`
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+
+	var valid []string
+	for i := 0; i < maxValidValues+5; i++ {
+		valid = append(valid, fmt.Sprintf("LOC%d", i))
+	}
+
+	err = newOptionNotFoundError("location", "XXXXXX", valid)
+	if !strings.HasSuffix(err.Detail(), "and 5 more.") {
+		t.Fatalf("expected truncated valid values, got %q", err.Detail())
+	}
+}
+
+func TestOptionDiagnostics_AttributePath(t *testing.T) {
+	err := newOptionNotFoundError("server model", "R441", []string{"R440"})
+
+	diags := optionDiagnostics(err, cty.GetAttrPath("server_model"))
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("server_model")) {
+		t.Fatalf("expected diagnostic pointing at server_model, got %v", diags)
+	}
+	if diags[0].Summary != "Can't find server model by: R441" {
+		t.Fatalf("unexpected summary: %q", diags[0].Summary)
+	}
+
+	slotPath := cty.GetAttrPath("slot").IndexInt(1).GetAttr("drive_model")
+	diags = optionDiagnostics(withAttributePath(err, slotPath), cty.GetAttrPath("slot"))
+	if !diags[0].AttributePath.Equals(slotPath) {
+		t.Fatalf("expected the path set by the lookup to win, got %v", diags[0].AttributePath)
+	}
+
+	diags = optionDiagnostics(fmt.Errorf("api error"), cty.GetAttrPath("location"))
+	if len(diags) != 1 || diags[0].AttributePath != nil || diags[0].Summary != "api error" {
+		t.Fatalf("expected other errors to be returned as is, got %v", diags)
+	}
+}
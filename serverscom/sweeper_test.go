@@ -7,20 +7,47 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	scgo "github.com/serverscom/serverscom-go-client/pkg"
+	"github.com/serverscom/terraform-provider-serverscom/internal/fakeapi"
 )
 
 func TestMain(m *testing.M) {
+	// SERVERSCOM_FAKE_API runs acceptance tests and sweepers against the in-memory fake api,
+	// the server lives until the test binary exits
+	if os.Getenv("SERVERSCOM_FAKE_API") != "" {
+		server := fakeapi.NewServer()
+
+		os.Setenv("SERVERSCOM_API_URL", server.URL)
+		os.Setenv("SERVERSCOM_API_TOKEN", "fake-token")
+
+		// the fake api changes statuses on every read, there's nothing to wait for
+		waiterDelayDivisor = 1000
+	}
+
 	resource.TestMain(m)
 }
 
 func createClient() (*scgo.Client, error) {
-	if os.Getenv("SERVERSCOM_TOKEN") == "" {
-		return nil, fmt.Errorf("you must set SERVERSCOM_TOKEN")
+	// SERVERSCOM_API_TOKEN is the one the provider reads
+	token := os.Getenv("SERVERSCOM_TOKEN")
+	if token == "" {
+		token = os.Getenv("SERVERSCOM_API_TOKEN")
+	}
+
+	if token == "" {
+		return nil, fmt.Errorf("you must set SERVERSCOM_TOKEN or SERVERSCOM_API_TOKEN")
 	}
 
 	if os.Getenv("SERVERSCOM_API_URL") == "" {
 		return nil, fmt.Errorf("you must set SERVERSCOM_API_URL")
 	}
 
-	return scgo.NewClientWithEndpoint(os.Getenv("SERVERSCOM_TOKEN"), os.Getenv("SERVERSCOM_API_URL")), nil
+	return scgo.NewClientWithEndpoint(token, os.Getenv("SERVERSCOM_API_URL")), nil
+}
+
+// testFakeAPIClient returns the client of an in-memory fake api, which is closed when the test ends
+func testFakeAPIClient(t *testing.T) *scgo.Client {
+	server := fakeapi.NewServer()
+	t.Cleanup(server.Close)
+
+	return scgo.NewClientWithEndpoint("fake-token", server.URL)
 }